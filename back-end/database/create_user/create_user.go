@@ -60,7 +60,14 @@ $$;
 		GRANT SELECT ON projects TO %s;
 		GRANT SELECT ON certificate TO %s;
 		GRANT SELECT ON project_images TO %s;
-	`, visitorUser, visitorUser, visitorUser, visitorUser, visitorUser)
+		GRANT SELECT ON project_slug_aliases TO %s;
+		GRANT SELECT ON search_documents TO %s;
+		GRANT SELECT ON tags TO %s;
+		GRANT SELECT ON project_tags TO %s;
+		GRANT SELECT ON project_skills TO %s;
+		GRANT SELECT ON certificate_skills TO %s;
+		GRANT SELECT ON skill_categories TO %s;
+	`, visitorUser, visitorUser, visitorUser, visitorUser, visitorUser, visitorUser, visitorUser, visitorUser, visitorUser, visitorUser, visitorUser, visitorUser)
 
 	if err := db.Exec(grantCustomerSQL).Error; err != nil {
 		log.Fatal("Failed to grant privileges to visitors_app:", err)
@@ -0,0 +1,11 @@
+package certif
+
+// computed validity of a certificate based on its expiry date
+const (
+	VALIDITY_VALID         = "valid"
+	VALIDITY_EXPIRING_SOON = "expiring_soon"
+	VALIDITY_EXPIRED       = "expired"
+)
+
+// unique index on credential_id of certificates that are not in the trash
+const CREDENTIAL_ID_INDEX = "uq_certificate_credential_id"
@@ -1,10 +1,14 @@
 package certif
 
 import (
+	"mime/multipart"
 	"net/http"
+	"strings"
 
 	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
 	"github.com/devanadindra/portfolio/back-end/utils/common"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	fileutils "github.com/devanadindra/portfolio/back-end/utils/file"
 	"github.com/devanadindra/portfolio/back-end/utils/respond"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -12,8 +16,14 @@ import (
 
 type Handler interface {
 	GetCertifById(ctx *gin.Context)
+	CreateCertif(ctx *gin.Context)
+	UpdateCertif(ctx *gin.Context)
+	ImportCertif(ctx *gin.Context)
 	DeleteCertif(ctx *gin.Context)
 	GetAllCertif(ctx *gin.Context)
+	UpdateCertifStatus(ctx *gin.Context)
+	CreatePreviewToken(ctx *gin.Context)
+	DeletePreviewToken(ctx *gin.Context)
 }
 
 type handler struct {
@@ -31,7 +41,7 @@ func NewHandler(service Service, validate *validator.Validate) Handler {
 func (h *handler) GetCertifById(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	res, err := h.service.GetCertifById(ctx, id)
+	res, err := h.service.GetCertifById(ctx, id, ctx.Query(constants.QUERY_PARAMS_PREVIEW))
 	if err != nil {
 		respond.Error(ctx, apierror.FromErr(err))
 		return
@@ -40,6 +50,86 @@ func (h *handler) GetCertifById(ctx *gin.Context) {
 	respond.Success(ctx, http.StatusOK, res)
 }
 
+func (h *handler) CreateCertif(ctx *gin.Context) {
+	var input CreateCertifReq
+	if err := ctx.ShouldBind(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	if err := validateCertifFiles(input.Image, input.Pdf); err != nil {
+		respond.Error(ctx, err)
+		return
+	}
+
+	res, err := h.service.CreateCertif(ctx, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusCreated, res)
+}
+
+func (h *handler) UpdateCertif(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input UpdateCertifReq
+	if err := ctx.ShouldBind(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	if err := validateCertifFiles(input.Image, input.Pdf); err != nil {
+		respond.Error(ctx, err)
+		return
+	}
+
+	res, err := h.service.UpdateCertif(ctx, id, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) ImportCertif(ctx *gin.Context) {
+	var input ImportCertifReq
+	if err := ctx.ShouldBind(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	if input.Badge != nil && strings.TrimSpace(input.Assertion) != "" {
+		respond.Error(ctx, apierror.InvalidBadgeInput())
+		return
+	}
+
+	res, err := h.service.ImportCertif(ctx, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusCreated, res)
+}
+
 func (h *handler) DeleteCertif(ctx *gin.Context) {
 	id := ctx.Param("id")
 
@@ -48,19 +138,26 @@ func (h *handler) DeleteCertif(ctx *gin.Context) {
 		return
 	}
 
-	respond.Success(ctx, http.StatusCreated, gin.H{"message": "kuis deleted successfully"})
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "certificate moved to trash"})
 }
 
 func (h *handler) GetAllCertif(ctx *gin.Context) {
-	filter, err := common.GetMetaData(ctx, h.validate, "created_at", "name")
+	filter, err := common.GetMetaData(ctx, h.validate, "created_at", "name", "issued_at", "expires_at")
 	if err != nil {
 		respond.Error(ctx, apierror.FromErr(err))
 		return
 	}
 
 	req := GetAllCertifReq{
-		Page:  filter.Page,
-		Limit: filter.Limit,
+		Page:     filter.Page,
+		Limit:    filter.Limit,
+		Issuer:   ctx.Query(constants.QUERY_PARAMS_ISSUER),
+		Validity: strings.ToLower(ctx.Query(constants.QUERY_PARAMS_VALIDITY)),
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
 	}
 
 	kuisList, total, err := h.service.GetAllCertif(ctx, req, *filter)
@@ -76,3 +173,63 @@ func (h *handler) GetAllCertif(ctx *gin.Context) {
 		"limit": filter.Limit,
 	})
 }
+
+func (h *handler) UpdateCertifStatus(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input UpdateCertifStatusReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	res, err := h.service.UpdateCertifStatus(ctx, id, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) CreatePreviewToken(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	res, err := h.service.CreatePreviewToken(ctx, id)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusCreated, res)
+}
+
+func (h *handler) DeletePreviewToken(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := h.service.DeletePreviewToken(ctx, id); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "preview link revoked successfully"})
+}
+
+// validateCertifFiles accepts at most one of image and pdf, checking the content of the pdf.
+func validateCertifFiles(image *multipart.FileHeader, pdf *multipart.FileHeader) error {
+	if image != nil && pdf != nil {
+		return apierror.InvalidCertifFile()
+	}
+	if image != nil && !fileutils.IsValidImage(image) {
+		return apierror.InvalidImageFile(image.Filename)
+	}
+	if pdf != nil && !fileutils.IsValidPDF(pdf) {
+		return apierror.InvalidPdfFile(pdf.Filename)
+	}
+	return nil
+}
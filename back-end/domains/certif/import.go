@@ -0,0 +1,154 @@
+package certif
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	fileutils "github.com/devanadindra/portfolio/back-end/utils/file"
+	"github.com/devanadindra/portfolio/back-end/utils/openbadge"
+)
+
+// ImportCertif creates a certificate from an Open Badges 2.0 or 3.0 assertion. A baked badge
+// is stored as the certificate image, otherwise the image referenced by the badge is used.
+func (s *service) ImportCertif(ctx context.Context, input ImportCertifReq) (*CertifRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, filename := []byte(input.Assertion), "assertion"
+	if input.Badge != nil {
+		data, err = readBadgeFile(input.Badge, s.badgeConfig.MaxSize)
+		if err != nil {
+			return nil, apierror.FromErr(err)
+		}
+		filename = input.Badge.Filename
+	}
+
+	badge, err := s.badges.Resolve(ctx, data, filename)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	if len(badge.Name) > 255 {
+		return nil, apierror.InvalidBadgeAssertion("the badge name is longer than 255 characters")
+	}
+	if len(badge.Issuer) > 255 {
+		return nil, apierror.InvalidBadgeAssertion("the issuer name is longer than 255 characters")
+	}
+	if badge.ExpiresAt != nil && badge.ExpiresAt.Before(badge.IssuedAt) {
+		return nil, apierror.InvalidBadgeAssertion("the badge expires before it was issued")
+	}
+
+	// ids and urls that do not fit the columns are left out rather than cut
+	var credentialID *string
+	if badge.CredentialID != "" && len(badge.CredentialID) <= 255 {
+		credentialID = &badge.CredentialID
+		if err := ensureNotImported(db.WithContext(ctx), badge.CredentialID); err != nil {
+			return nil, err
+		}
+	}
+
+	// only an uploaded baked badge is the image itself, an assertion points to its image
+	var imgData []byte
+	var ext string
+	if input.Badge != nil && openbadge.IsBadgeImage(data) {
+		imgData, ext, err = openbadge.SafeImage(data)
+	} else {
+		imgData, ext, err = s.badges.Image(ctx, badge.Image)
+	}
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	certif := Certificate{
+		ID:            uuid.New(),
+		Name:          badge.Name,
+		CertifLink:    common.Ternary(len(badge.VerificationUrl) <= 255, badge.VerificationUrl, ""),
+		Issuer:        badge.Issuer,
+		IssuedAt:      &badge.IssuedAt,
+		ExpiresAt:     badge.ExpiresAt,
+		CredentialID:  credentialID,
+		SkillsCovered: cleanSkillsCovered(badge.Tags),
+		Status:        common.Ternary(input.Status != "", input.Status, constants.STATUS_PUBLISHED),
+		PublishAt:     input.PublishAt,
+	}
+
+	certif.ImgUrl, err = saveCertifBytes(certif.ID, ext, imgData)
+	if err != nil {
+		removeCertifFiles(ctx, certif.ImgUrl)
+		return nil, apierror.FromErr(err)
+	}
+
+	if err := db.WithContext(ctx).Create(&certif).Error; err != nil {
+		removeCertifFiles(ctx, certif.ImgUrl)
+		if common.IsUniqueViolation(err, CREDENTIAL_ID_INDEX) {
+			// imported by a concurrent request after the check above
+			if err := ensureNotImported(db.WithContext(ctx), badge.CredentialID); err != nil {
+				return nil, apierror.FromErr(err)
+			}
+		}
+		return nil, apierror.FromErr(err)
+	}
+
+	res := s.toCertifRes(certif)
+	return &res, nil
+}
+
+func readBadgeFile(file *multipart.FileHeader, maxSize int64) ([]byte, error) {
+	if file.Size > maxSize {
+		return nil, apierror.BadgeTooLarge(maxSize)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	return io.ReadAll(io.LimitReader(src, maxSize+1))
+}
+
+// saveCertifBytes writes an image that did not come from an upload, like a downloaded badge.
+func saveCertifBytes(certifID uuid.UUID, ext string, data []byte) (string, error) {
+	if err := os.MkdirAll(certifUploadDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	filename, err := fileutils.GenerateMediaName(certifID.String())
+	if err != nil {
+		return "", err
+	}
+
+	filename += strings.ToLower(ext)
+	url := "/" + certifUploadDir + "/" + filename
+	if err := os.WriteFile(filepath.Join(certifUploadDir, filename), data, 0o644); err != nil {
+		return url, err
+	}
+
+	return url, nil
+}
+
+// ensureNotImported rejects a badge whose assertion id is already used by a certificate.
+func ensureNotImported(db *gorm.DB, credentialID string) error {
+	var existing Certificate
+	err := db.Select("id").Where("credential_id = ?", credentialID).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return apierror.CertifAlreadyImported(existing.ID.String())
+}
@@ -0,0 +1,145 @@
+package certif
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+	"github.com/devanadindra/portfolio/back-end/utils/openbadge"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+var testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type stubResolver struct {
+	badge  openbadge.Badge
+	images []string
+}
+
+func (r *stubResolver) Resolve(ctx context.Context, data []byte, filename string) (*openbadge.Badge, error) {
+	badge := r.badge
+	return &badge, nil
+}
+
+func (r *stubResolver) Image(ctx context.Context, src string) ([]byte, string, error) {
+	r.images = append(r.images, src)
+	return testPNG, ".png", nil
+}
+
+func newImportService(t *testing.T, badges openbadge.Resolver) (*service, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock := testutil.NewMockDB(t)
+	return &service{
+		badgeConfig: config.OpenBadge{MaxSize: 1 << 20},
+		dbSelector:  dbselector.NewDBService(&database.OwnerDB{DB: db}, &database.VisitorsDB{DB: db}),
+		badges:      badges,
+	}, mock
+}
+
+func TestImportCertifConcurrentDuplicate(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	badges := &stubResolver{badge: openbadge.Badge{
+		Name:         "Go Developer",
+		Issuer:       "Example",
+		Image:        "https://example.com/badge.png",
+		IssuedAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		CredentialID: "https://example.com/assertions/1",
+	}}
+	s, mock := newImportService(t, badges)
+	existing := uuid.New()
+
+	mock.ExpectQuery(`SELECT "id" FROM "certificate" WHERE credential_id = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "certificate"`).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: CREDENTIAL_ID_INDEX})
+	mock.ExpectRollback()
+	mock.ExpectQuery(`SELECT "id" FROM "certificate" WHERE credential_id = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(existing))
+
+	_, err := s.ImportCertif(context.Background(), ImportCertifReq{Assertion: "https://example.com/assertions/1"})
+	if code := testutil.StatusOf(err); code != http.StatusConflict {
+		t.Fatalf("status = %d (%v), want %d", code, err, http.StatusConflict)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func expectImport(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT "id" FROM "certificate" WHERE credential_id = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "certificate"`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	mock.ExpectCommit()
+}
+
+func TestImportCertifImageSource(t *testing.T) {
+	badge := openbadge.Badge{
+		Name:         "Go Developer",
+		Issuer:       "Example",
+		Image:        "https://example.com/badge.png",
+		IssuedAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		CredentialID: "https://example.com/assertions/1",
+	}
+
+	tests := []struct {
+		name    string
+		input   func(t *testing.T) ImportCertifReq
+		fetched bool
+	}{
+		{
+			name: "baked png upload",
+			input: func(t *testing.T) ImportCertifReq {
+				return ImportCertifReq{Badge: testutil.FormFile(t, "badge.png", testPNG)}
+			},
+		},
+		{
+			name: "assertion json upload",
+			input: func(t *testing.T) ImportCertifReq {
+				return ImportCertifReq{Badge: testutil.FormFile(t, "assertion.json", []byte(`{"id":"x"}`))}
+			},
+			fetched: true,
+		},
+		{
+			// looks like an image when sniffed, but it is not an uploaded badge
+			name: "assertion text",
+			input: func(t *testing.T) ImportCertifReq {
+				return ImportCertifReq{Assertion: `<svg onload="alert(1)"></svg>`}
+			},
+			fetched: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+
+			badges := &stubResolver{badge: badge}
+			s, mock := newImportService(t, badges)
+			expectImport(mock)
+
+			if _, err := s.ImportCertif(context.Background(), tt.input(t)); err != nil {
+				t.Fatal(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+
+			if fetched := len(badges.images) == 1 && badges.images[0] == badge.Image; fetched != tt.fetched {
+				t.Errorf("badge image fetched = %v, want %v", fetched, tt.fetched)
+			}
+		})
+	}
+}
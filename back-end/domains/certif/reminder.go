@@ -0,0 +1,173 @@
+package certif
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devanadindra/portfolio/back-end/domains/notification"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	"github.com/devanadindra/portfolio/back-end/utils/logger"
+	"github.com/devanadindra/portfolio/back-end/utils/mailer"
+	"github.com/devanadindra/portfolio/back-end/utils/scheduler"
+)
+
+const entityTypeCertificate = "certificate"
+
+func (s *service) ExpiryReminderJob() scheduler.Job {
+	return scheduler.Job{
+		Name:     "certificate-expiry-reminder",
+		Interval: s.certifConfig.ReminderInterval,
+		Run:      s.SendExpiryReminders,
+	}
+}
+
+// SendExpiryReminders notifies the owner about published certificates reaching one of the
+// configured lead times. Only the most urgent lead time reached is sent, the ones already
+// passed are recorded as well so a server that was down for a while does not send a burst
+// of reminders.
+func (s *service) SendExpiryReminders(ctx context.Context) error {
+	leads := reminderLeadDays(s.certifConfig.ReminderLeadDays)
+	if len(leads) == 0 {
+		return nil
+	}
+
+	db := s.OwnerDB.WithContext(ctx)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	var certifs []Certificate
+	if err := db.Where("status = ?", constants.STATUS_PUBLISHED).
+		Where("expires_at >= ? AND expires_at <= ?", today, today.AddDate(0, 0, leads[len(leads)-1])).
+		Order("expires_at ASC").
+		Find(&certifs).Error; err != nil {
+		return err
+	}
+	if len(certifs) == 0 {
+		return nil
+	}
+
+	var emails []string
+	if err := db.Table("owner").Pluck("email", &emails).Error; err != nil {
+		return err
+	}
+
+	for _, certif := range certifs {
+		expiresAt := certif.ExpiresAt.UTC().Truncate(24 * time.Hour)
+		daysLeft := int(expiresAt.Sub(today).Hours() / 24)
+
+		// leads are sorted ascending, so the first one reached is the most urgent
+		var due []int
+		for _, lead := range leads {
+			if daysLeft <= lead {
+				due = append(due, lead)
+			}
+		}
+		if len(due) == 0 {
+			continue
+		}
+
+		sent, err := s.recordReminder(db, certif, expiresAt, daysLeft, due)
+		if err != nil {
+			return err
+		}
+		if !sent {
+			continue
+		}
+
+		// the email goes out after the reminder is recorded, a failure is logged but never resent
+		if len(emails) > 0 {
+			if err := s.mailer.Send(ctx, mailer.Message{
+				To:      emails,
+				Subject: reminderTitle(certif, daysLeft),
+				Body:    reminderBody(certif, expiresAt, daysLeft),
+			}); err != nil {
+				logger.Error(ctx, "%v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// recordReminder stores the reminder rows and the in-app notification. It returns false when
+// the most urgent reminder was already sent, for example before a restart.
+func (s *service) recordReminder(db *gorm.DB, certif Certificate, expiresAt time.Time, daysLeft int, due []int) (bool, error) {
+	sent := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		rows := make([]CertificateReminder, len(due))
+		for i, lead := range due {
+			rows[i] = CertificateReminder{
+				CertificateID: certif.ID,
+				LeadDays:      lead,
+				ExpiresAt:     expiresAt,
+			}
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows[0])
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		// lead times already passed are marked as sent without their own notification
+		if passed := rows[1:]; len(passed) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&passed).Error; err != nil {
+				return err
+			}
+		}
+
+		entityType := entityTypeCertificate
+		if err := tx.Create(&notification.Notification{
+			Type:       notification.TYPE_CERTIFICATE_EXPIRY,
+			Title:      reminderTitle(certif, daysLeft),
+			Body:       reminderBody(certif, expiresAt, daysLeft),
+			EntityType: &entityType,
+			EntityID:   &certif.ID,
+		}).Error; err != nil {
+			return err
+		}
+
+		sent = true
+		return nil
+	})
+
+	return sent, err
+}
+
+// reminderLeadDays drops invalid and repeated values and sorts them ascending.
+func reminderLeadDays(leads []int) []int {
+	res := make([]int, 0, len(leads))
+	for _, lead := range leads {
+		if lead > 0 && !slices.Contains(res, lead) {
+			res = append(res, lead)
+		}
+	}
+	slices.Sort(res)
+	return res
+}
+
+func reminderTitle(certif Certificate, daysLeft int) string {
+	if daysLeft == 0 {
+		return fmt.Sprintf("Certificate %q expires today", certif.Name)
+	}
+	return fmt.Sprintf("Certificate %q expires in %d days", certif.Name, daysLeft)
+}
+
+func reminderBody(certif Certificate, expiresAt time.Time, daysLeft int) string {
+	body := fmt.Sprintf("Your certificate %q", certif.Name)
+	if certif.Issuer != "" {
+		body += fmt.Sprintf(" issued by %s", certif.Issuer)
+	}
+	body += fmt.Sprintf(" expires on %s", expiresAt.Format("2 January 2006"))
+	if daysLeft > 0 {
+		body += fmt.Sprintf(" (%d days left)", daysLeft)
+	}
+	body += ".\n\nRenew it and update the expiry date in the portfolio so it is not shown as expired."
+	return body
+}
@@ -0,0 +1,58 @@
+package certif
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	"github.com/devanadindra/portfolio/back-end/utils/mailer"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+func TestSendExpiryRemindersOnlyPublished(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	sender := mailer.NewMemorySender()
+	s := &service{
+		certifConfig: config.Certificate{ReminderLeadDays: []int{30, 7}},
+		OwnerDB:      &database.OwnerDB{DB: db},
+		mailer:       sender,
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "certificate" WHERE status = $1 AND (expires_at >= $2 AND expires_at <= $3)`)).
+		WithArgs(constants.STATUS_PUBLISHED, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if err := s.SendExpiryReminders(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	if len(sender.Messages()) != 0 {
+		t.Errorf("sent %d emails, want none", len(sender.Messages()))
+	}
+}
+
+func TestReminderLeadDays(t *testing.T) {
+	got := reminderLeadDays([]int{30, 7, 0, -1, 60, 7})
+	if want := []int{7, 30, 60}; !slices.Equal(got, want) {
+		t.Errorf("reminderLeadDays() = %v, want %v", got, want)
+	}
+}
+
+func TestReminderTitle(t *testing.T) {
+	certif := Certificate{Name: "CKA"}
+
+	if got, want := reminderTitle(certif, 0), `Certificate "CKA" expires today`; got != want {
+		t.Errorf("reminderTitle(0) = %q, want %q", got, want)
+	}
+	if got, want := reminderTitle(certif, 7), `Certificate "CKA" expires in 7 days`; got != want {
+		t.Errorf("reminderTitle(7) = %q, want %q", got, want)
+	}
+}
@@ -1,10 +1,63 @@
 package certif
 
+import (
+	"mime/multipart"
+	"time"
+)
+
 type DeleteKuisReq struct {
 	ID string `json:"id" binding:"required"`
 }
 
 type GetAllCertifReq struct {
-	Page  int64
-	Limit int64
+	Page     int64
+	Limit    int64
+	Issuer   string `validate:"max=255"`
+	Validity string `validate:"omitempty,oneof=valid expiring_soon expired"`
+}
+
+type CreateCertifReq struct {
+	Name       string `form:"name" validate:"required,max=255"`
+	CertifLink string `form:"certif_link" validate:"omitempty,url,max=255"`
+	// a certificate is uploaded either as an image or as a PDF
+	Image         *multipart.FileHeader `form:"image" validate:"required_without=Pdf"`
+	Pdf           *multipart.FileHeader `form:"pdf"`
+	Issuer        string                `form:"issuer" validate:"max=255"`
+	IssuedAt      *time.Time            `form:"issued_at" time_format:"2006-01-02"`
+	ExpiresAt     *time.Time            `form:"expires_at" time_format:"2006-01-02"`
+	CredentialID  string                `form:"credential_id" validate:"max=255"`
+	SkillsCovered []string              `form:"skills_covered" validate:"omitempty,dive,max=100"`
+	Status        string                `form:"status" validate:"omitempty,oneof=draft published archived"`
+	PublishAt     *time.Time            `form:"publish_at"`
+}
+
+type UpdateCertifReq struct {
+	Name *string `form:"name" validate:"omitempty,min=1,max=255"`
+	// an empty string removes the link
+	CertifLink *string               `form:"certif_link" validate:"omitempty,url|eq=,max=255"`
+	Image      *multipart.FileHeader `form:"image"`
+	// a new PDF replaces both the stored PDF and the image with its thumbnail
+	Pdf    *multipart.FileHeader `form:"pdf"`
+	Issuer *string               `form:"issuer" validate:"omitempty,max=255"`
+	// an empty value removes the date
+	IssuedAt  *time.Time `form:"issued_at" time_format:"2006-01-02"`
+	ExpiresAt *time.Time `form:"expires_at" time_format:"2006-01-02"`
+	// an empty string removes the credential id
+	CredentialID *string `form:"credential_id" validate:"omitempty,max=255"`
+	// replaces the whole list, send a single empty value to clear it
+	SkillsCovered []string `form:"skills_covered" validate:"omitempty,dive,max=100"`
+}
+
+type ImportCertifReq struct {
+	// a baked PNG or SVG badge, or an assertion JSON file
+	Badge *multipart.FileHeader `form:"badge" validate:"required_without=Assertion"`
+	// an assertion JSON, a signed assertion or the url of a hosted assertion
+	Assertion string     `form:"assertion"`
+	Status    string     `form:"status" validate:"omitempty,oneof=draft published archived"`
+	PublishAt *time.Time `form:"publish_at"`
+}
+
+type UpdateCertifStatusReq struct {
+	Status    string     `json:"status" validate:"required,oneof=draft published archived"`
+	PublishAt *time.Time `json:"publish_at"`
 }
@@ -1,8 +1,29 @@
 package certif
 
+import (
+	"time"
+
+	"github.com/devanadindra/portfolio/back-end/domains/skill"
+)
+
 type CertifRes struct {
-	ID         string `json:"id"`
-	Name       string `json:"name"`
-	ImgUrl     string `json:"img_url"`
-	CertifLink string `json:"certif_link"`
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	ImgUrl     string     `json:"img_url"`
+	CertifLink string     `json:"certif_link"`
+	Status     string     `json:"status"`
+	PublishAt  *time.Time `json:"publish_at"`
+	// fields below were added later, existing ones above keep their names
+	Issuer        string                  `json:"issuer"`
+	IssuedAt      *time.Time              `json:"issued_at"`
+	ExpiresAt     *time.Time              `json:"expires_at"`
+	CredentialID  *string                 `json:"credential_id"`
+	SkillsCovered []string                `json:"skills_covered"`
+	Validity      string                  `json:"validity"`
+	PdfUrl        *string                 `json:"pdf_url"`
+	Skills        []skill.SkillSummaryRes `json:"skills"`
+}
+
+type PreviewTokenRes struct {
+	PreviewToken string `json:"preview_token"`
 }
@@ -3,35 +3,61 @@ package certif
 import (
 	"context"
 	"errors"
-	"fmt"
+	"time"
 
 	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/domains/skill"
 	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
 	"github.com/devanadindra/portfolio/back-end/utils/config"
 	"github.com/devanadindra/portfolio/back-end/utils/constants"
 	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+	"github.com/devanadindra/portfolio/back-end/utils/mailer"
+	"github.com/devanadindra/portfolio/back-end/utils/openbadge"
+	"github.com/devanadindra/portfolio/back-end/utils/scheduler"
+	"github.com/devanadindra/portfolio/back-end/utils/thumbnail"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Service interface {
 	GetAllCertif(ctx context.Context, input GetAllCertifReq, filter constants.FilterReq) (*[]CertifRes, int64, error)
-	GetCertifById(ctx context.Context, kuisId string) (*CertifRes, error)
-	DeleteCertif(ctx context.Context, kuisId string) error
+	GetCertifById(ctx context.Context, kuisId string, previewToken string) (*CertifRes, error)
+	CreateCertif(ctx context.Context, input CreateCertifReq) (*CertifRes, error)
+	UpdateCertif(ctx context.Context, certifId string, input UpdateCertifReq) (*CertifRes, error)
+	ImportCertif(ctx context.Context, input ImportCertifReq) (*CertifRes, error)
+	DeleteCertif(ctx context.Context, certifId string) error
+	UpdateCertifStatus(ctx context.Context, certifId string, input UpdateCertifStatusReq) (*CertifRes, error)
+	CreatePreviewToken(ctx context.Context, certifId string) (*PreviewTokenRes, error)
+	DeletePreviewToken(ctx context.Context, certifId string) error
+	SendExpiryReminders(ctx context.Context) error
+	ExpiryReminderJob() scheduler.Job
 }
 
 type service struct {
-	authConfig config.Auth
-	dbSelector *dbselector.DBService
-	VisitorsDB *database.VisitorsDB
-	OwnerDB    *database.OwnerDB
+	authConfig   config.Auth
+	certifConfig config.Certificate
+	badgeConfig  config.OpenBadge
+	dbSelector   *dbselector.DBService
+	VisitorsDB   *database.VisitorsDB
+	OwnerDB      *database.OwnerDB
+	mailer       mailer.Sender
+	pdfRenderer  thumbnail.PdfRenderer
+	badges       openbadge.Resolver
 }
 
-func NewService(config *config.Config, dbSelector *dbselector.DBService, VisitorsDB *database.VisitorsDB, OwnerDB *database.OwnerDB) Service {
+func NewService(config *config.Config, dbSelector *dbselector.DBService, VisitorsDB *database.VisitorsDB, OwnerDB *database.OwnerDB, mailer mailer.Sender, pdfRenderer thumbnail.PdfRenderer, badges openbadge.Resolver) Service {
 	return &service{
-		authConfig: config.Auth,
-		dbSelector: dbSelector,
-		VisitorsDB: VisitorsDB,
-		OwnerDB:    OwnerDB,
+		authConfig:   config.Auth,
+		certifConfig: config.Certificate,
+		badgeConfig:  config.OpenBadge,
+		dbSelector:   dbSelector,
+		VisitorsDB:   VisitorsDB,
+		OwnerDB:      OwnerDB,
+		mailer:       mailer,
+		pdfRenderer:  pdfRenderer,
+		badges:       badges,
 	}
 }
 
@@ -44,70 +70,196 @@ func (s *service) GetAllCertif(ctx context.Context, input GetAllCertifReq, filte
 		return nil, 0, err
 	}
 
-	query := db.WithContext(ctx).Model(&Certificate{})
+	query := db.WithContext(ctx).
+		Model(&Certificate{}).
+		Scopes(
+			s.dbSelector.VisibleScope(ctx),
+			common.FilterScope(filter, "name", "issuer"),
+			issuerFilterScope(input.Issuer),
+			validityFilterScope(input.Validity, s.certifConfig.ExpiringSoon),
+		)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	offset := (filter.Page - 1) * filter.Limit
-
 	if err := query.
-		Order(fmt.Sprintf("%s %s", filter.OrderBy, filter.SortOrder)).
-		Limit(int(filter.Limit)).
-		Offset(int(offset)).
+		Scopes(common.PaginateScope(filter)).
+		Preload("Skills", skill.OrderedSkills).
 		Find(&certifList).Error; err != nil {
 		return nil, 0, err
 	}
 
 	res := make([]CertifRes, len(certifList))
 	for i, k := range certifList {
-		res[i] = CertifRes{
-			ID:         k.ID.String(),
-			Name:       k.Name,
-			ImgUrl:     k.ImgUrl,
-			CertifLink: k.CertifLink,
-		}
+		res[i] = s.toCertifRes(k)
 	}
 
 	return &res, total, nil
 }
 
-func (s *service) GetCertifById(ctx context.Context, kuisId string) (*CertifRes, error) {
+func (s *service) GetCertifById(ctx context.Context, kuisId string, previewToken string) (*CertifRes, error) {
 	db, err := s.dbSelector.GetDBByRole(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var certif Certificate
-	err = db.WithContext(ctx).
-		Where("id =?", kuisId).
-		Find(&certif).Error
+	certif, err := findCertif(db.WithContext(ctx), kuisId)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	// unpublished certificates look missing unless the owner asks or a valid preview link is used
+	if !s.dbSelector.IsOwner(ctx) && !common.IsPublished(certif.Status, certif.PublishAt) {
+		if previewToken == "" || certif.PreviewToken == nil || !common.TokenEquals(*certif.PreviewToken, previewToken) {
+			return nil, apierror.CertifNotFound(kuisId)
+		}
+	}
+
+	res := s.toCertifRes(certif)
+	return &res, nil
+}
+
+func (s *service) CreateCertif(ctx context.Context, input CreateCertifReq) (*CertifRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	res := CertifRes{
-		ID:         certif.ID.String(),
-		Name:       certif.Name,
-		ImgUrl:     certif.ImgUrl,
-		CertifLink: certif.CertifLink,
+	input.IssuedAt = optionalDate(input.IssuedAt)
+	input.ExpiresAt = optionalDate(input.ExpiresAt)
+	if input.IssuedAt != nil && input.ExpiresAt != nil && input.ExpiresAt.Before(*input.IssuedAt) {
+		return nil, apierror.InvalidCertifValidity()
+	}
+
+	certif := Certificate{
+		ID:            uuid.New(),
+		Name:          input.Name,
+		CertifLink:    input.CertifLink,
+		Issuer:        input.Issuer,
+		IssuedAt:      input.IssuedAt,
+		ExpiresAt:     input.ExpiresAt,
+		CredentialID:  common.Ternary(input.CredentialID != "", &input.CredentialID, nil),
+		SkillsCovered: cleanSkillsCovered(input.SkillsCovered),
+		Status:        common.Ternary(input.Status != "", input.Status, constants.STATUS_PUBLISHED),
+		PublishAt:     input.PublishAt,
 	}
 
+	var pdfUrl string
+	if input.Pdf != nil {
+		pdfUrl, certif.ImgUrl, err = s.saveCertifPdf(ctx, certif.ID, input.Pdf)
+		certif.PdfUrl = &pdfUrl
+	} else {
+		certif.ImgUrl, err = saveCertifFile(ctx, certif.ID, input.Image)
+	}
+	if err != nil {
+		removeCertifFiles(ctx, certif.ImgUrl, pdfUrl)
+		return nil, apierror.FromErr(err)
+	}
+
+	if err := db.WithContext(ctx).Create(&certif).Error; err != nil {
+		removeCertifFiles(ctx, certif.ImgUrl, pdfUrl)
+		if common.IsUniqueViolation(err, CREDENTIAL_ID_INDEX) {
+			return nil, apierror.CertifCredentialIdTaken()
+		}
+		return nil, apierror.FromErr(err)
+	}
+
+	res := s.toCertifRes(certif)
 	return &res, nil
 }
 
-func (s *service) DeleteCertif(ctx context.Context, certifId string) error {
+func (s *service) UpdateCertif(ctx context.Context, certifId string, input UpdateCertifReq) (*CertifRes, error) {
 	db, err := s.dbSelector.GetDBByRole(ctx)
 	if err != nil {
-		return apierror.FromErr(err)
+		return nil, err
+	}
+
+	id, err := uuid.Parse(certifId)
+	if err != nil {
+		return nil, apierror.InvalidCertifId()
+	}
+
+	// new files are written before the transaction so rendering a PDF does not hold it open
+	var imgUrl, pdfUrl string
+	if input.Pdf != nil {
+		pdfUrl, imgUrl, err = s.saveCertifPdf(ctx, id, input.Pdf)
+	} else if input.Image != nil {
+		imgUrl, err = saveCertifFile(ctx, id, input.Image)
+	}
+	if err != nil {
+		removeCertifFiles(ctx, imgUrl, pdfUrl)
+		return nil, apierror.FromErr(err)
 	}
 
 	var certif Certificate
-	if err := db.WithContext(ctx).First(&certif, "id = ?", certifId).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return apierror.LatihanNotFound(certifId)
+	var oldUrls []string
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		certif, err = findCertif(tx, certifId)
+		if err != nil {
+			return err
+		}
+
+		if input.Name != nil {
+			certif.Name = *input.Name
+		}
+		if input.CertifLink != nil {
+			certif.CertifLink = *input.CertifLink
+		}
+		if input.Issuer != nil {
+			certif.Issuer = *input.Issuer
+		}
+		if input.IssuedAt != nil {
+			certif.IssuedAt = optionalDate(input.IssuedAt)
+		}
+		if input.ExpiresAt != nil {
+			certif.ExpiresAt = optionalDate(input.ExpiresAt)
+		}
+		if input.CredentialID != nil {
+			certif.CredentialID = common.Ternary(*input.CredentialID != "", input.CredentialID, nil)
+		}
+		if input.SkillsCovered != nil {
+			certif.SkillsCovered = cleanSkillsCovered(input.SkillsCovered)
+		}
+
+		if certif.IssuedAt != nil && certif.ExpiresAt != nil && certif.ExpiresAt.Before(*certif.IssuedAt) {
+			return apierror.InvalidCertifValidity()
+		}
+
+		if imgUrl != "" {
+			// a new image replaces the stored PDF too, otherwise the old PDF stays linked
+			oldUrls = append(oldUrls, certif.ImgUrl, common.GetValueFromPointer(certif.PdfUrl))
+			certif.ImgUrl = imgUrl
+			certif.PdfUrl = common.Ternary(pdfUrl != "", &pdfUrl, nil)
 		}
+
+		return tx.Omit(clause.Associations).Save(&certif).Error
+	})
+	if err != nil {
+		removeCertifFiles(ctx, imgUrl, pdfUrl)
+		if common.IsUniqueViolation(err, CREDENTIAL_ID_INDEX) {
+			return nil, apierror.CertifCredentialIdTaken()
+		}
+		return nil, apierror.FromErr(err)
+	}
+
+	// the old files are only removed once the new ones are committed
+	removeCertifFiles(ctx, oldUrls...)
+
+	res := s.toCertifRes(certif)
+	return &res, nil
+}
+
+// DeleteCertif moves the certificate to the trash, its files are removed when the trash
+// is purged.
+func (s *service) DeleteCertif(ctx context.Context, certifId string) error {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return apierror.FromErr(err)
+	}
+
+	certif, err := findCertif(db.WithContext(ctx), certifId)
+	if err != nil {
 		return apierror.FromErr(err)
 	}
 
@@ -117,3 +269,112 @@ func (s *service) DeleteCertif(ctx context.Context, certifId string) error {
 
 	return nil
 }
+
+func (s *service) UpdateCertifStatus(ctx context.Context, certifId string, input UpdateCertifStatusReq) (*CertifRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	certif, err := findCertif(db.WithContext(ctx), certifId)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	certif.Status = input.Status
+	certif.PublishAt = input.PublishAt
+
+	if err := db.WithContext(ctx).Model(&certif).
+		Select("status", "publish_at").
+		Updates(&certif).Error; err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	res := s.toCertifRes(certif)
+	return &res, nil
+}
+
+func (s *service) CreatePreviewToken(ctx context.Context, certifId string) (*PreviewTokenRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	certif, err := findCertif(db.WithContext(ctx), certifId)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	token, err := common.GenerateToken(32)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	if err := db.WithContext(ctx).Model(&certif).
+		Update("preview_token", token).Error; err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return &PreviewTokenRes{PreviewToken: token}, nil
+}
+
+func (s *service) DeletePreviewToken(ctx context.Context, certifId string) error {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return err
+	}
+
+	certif, err := findCertif(db.WithContext(ctx), certifId)
+	if err != nil {
+		return apierror.FromErr(err)
+	}
+
+	if err := db.WithContext(ctx).Model(&certif).
+		Update("preview_token", nil).Error; err != nil {
+		return apierror.FromErr(err)
+	}
+
+	return nil
+}
+
+func findCertif(tx *gorm.DB, certifId string) (Certificate, error) {
+	var certif Certificate
+
+	id, err := uuid.Parse(certifId)
+	if err != nil {
+		return certif, apierror.InvalidCertifId()
+	}
+
+	if err := tx.Preload("Skills", skill.OrderedSkills).First(&certif, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return certif, apierror.CertifNotFound(certifId)
+		}
+		return certif, err
+	}
+
+	return certif, nil
+}
+
+func (s *service) toCertifRes(c Certificate) CertifRes {
+	skills := []string(c.SkillsCovered)
+	if skills == nil {
+		skills = []string{}
+	}
+
+	return CertifRes{
+		ID:            c.ID.String(),
+		Name:          c.Name,
+		ImgUrl:        c.ImgUrl,
+		CertifLink:    c.CertifLink,
+		Status:        c.Status,
+		PublishAt:     c.PublishAt,
+		Issuer:        c.Issuer,
+		IssuedAt:      c.IssuedAt,
+		ExpiresAt:     c.ExpiresAt,
+		CredentialID:  c.CredentialID,
+		SkillsCovered: skills,
+		Validity:      validity(c.ExpiresAt, time.Now(), s.certifConfig.ExpiringSoon),
+		PdfUrl:        c.PdfUrl,
+		Skills:        skill.ToSkillSummaries(c.Skills),
+	}
+}
@@ -0,0 +1,223 @@
+package certif
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+	"github.com/devanadindra/portfolio/back-end/utils/thumbnail"
+)
+
+type stubRenderer struct {
+	err error
+}
+
+func (r stubRenderer) RenderFirstPage(ctx context.Context, pdfPath string, pngPath string) error {
+	if r.err != nil {
+		return r.err
+	}
+	return os.WriteFile(pngPath, []byte("png"), 0o644)
+}
+
+func writeUpload(t *testing.T, url string) {
+	t.Helper()
+	path := url[1:]
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestUpdateCertifImageReplacesPdf(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	db, mock := testutil.NewMockDB(t)
+	s := &service{
+		dbSelector:  dbselector.NewDBService(&database.OwnerDB{DB: db}, &database.VisitorsDB{DB: db}),
+		pdfRenderer: stubRenderer{},
+	}
+
+	id := uuid.New()
+	oldImg := "/uploads/certificate/old.png"
+	oldPdf := "/uploads/certificate/old.pdf"
+	writeUpload(t, oldImg)
+	writeUpload(t, oldPdf)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "certificate" WHERE id = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "img_url", "pdf_url", "status"}).
+			AddRow(id, "CKA", oldImg, oldPdf, "published"))
+	mock.ExpectQuery(`FROM "certificate_skills"`).
+		WillReturnRows(sqlmock.NewRows([]string{"certificate_id", "skill_id"}))
+	mock.ExpectExec(`UPDATE "certificate" SET`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	res, err := s.UpdateCertif(context.Background(), id.String(), UpdateCertifReq{
+		Image: testutil.FormFile(t, "new.png", []byte("\x89PNG\r\n\x1a\n")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.PdfUrl != nil {
+		t.Errorf("pdf url = %q, want none after an image upload", *res.PdfUrl)
+	}
+	if res.ImgUrl == oldImg || !exists(res.ImgUrl[1:]) {
+		t.Errorf("new image %q was not stored", res.ImgUrl)
+	}
+	if exists(oldImg[1:]) || exists(oldPdf[1:]) {
+		t.Error("old image and pdf were not removed after commit")
+	}
+}
+
+func TestUpdateCertifRollbackKeepsOldFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	db, mock := testutil.NewMockDB(t)
+	s := &service{
+		dbSelector:  dbselector.NewDBService(&database.OwnerDB{DB: db}, &database.VisitorsDB{DB: db}),
+		pdfRenderer: stubRenderer{},
+	}
+
+	id := uuid.New()
+	oldImg := "/uploads/certificate/old.png"
+	writeUpload(t, oldImg)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "certificate" WHERE id = \$1`).
+		WillReturnError(fmt.Errorf("connection reset"))
+	mock.ExpectRollback()
+
+	_, err := s.UpdateCertif(context.Background(), id.String(), UpdateCertifReq{
+		Pdf: testutil.FormFile(t, "new.pdf", []byte("%PDF-1.4")),
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	entries, _ := os.ReadDir(certifUploadDir)
+	if len(entries) != 1 || !exists(oldImg[1:]) {
+		t.Errorf("uploads after rollback = %v, want only the old image", entries)
+	}
+}
+
+func TestSaveCertifPdfRendererMissing(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	s := &service{pdfRenderer: stubRenderer{err: fmt.Errorf("%w: pdftoppm", thumbnail.ErrRendererUnavailable)}}
+	_, _, err := s.saveCertifPdf(context.Background(), uuid.New(), testutil.FormFile(t, "a.pdf", []byte("%PDF-1.4")))
+	if code := testutil.StatusOf(apierror.FromErr(err)); code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", code, http.StatusInternalServerError)
+	}
+
+	s.pdfRenderer = stubRenderer{err: errors.New("syntax error")}
+	_, _, err = s.saveCertifPdf(context.Background(), uuid.New(), testutil.FormFile(t, "a.pdf", []byte("not a pdf")))
+	if code := testutil.StatusOf(apierror.FromErr(err)); code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestCreateCertif(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	db, mock := testutil.NewMockDB(t)
+	s := &service{dbSelector: dbselector.NewDBService(&database.OwnerDB{DB: db}, &database.VisitorsDB{DB: db})}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "certificate"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	res, err := s.CreateCertif(context.Background(), CreateCertifReq{
+		Name:  "CKA",
+		Image: testutil.FormFile(t, "cert.PNG", []byte("\x89PNG\r\n\x1a\n")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Dir(res.ImgUrl) != "/"+certifUploadDir || filepath.Ext(res.ImgUrl) != ".png" || !exists(res.ImgUrl[1:]) {
+		t.Errorf("image %q was not stored", res.ImgUrl)
+	}
+	if res.PdfUrl != nil {
+		t.Errorf("pdf url = %q, want none", *res.PdfUrl)
+	}
+}
+
+// the stored image must not be left behind when the row is not written
+func TestCreateCertifRemovesImageOnError(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	db, mock := testutil.NewMockDB(t)
+	s := &service{dbSelector: dbselector.NewDBService(&database.OwnerDB{DB: db}, &database.VisitorsDB{DB: db})}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "certificate"`).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	_, err := s.CreateCertif(context.Background(), CreateCertifReq{
+		Name:  "CKA",
+		Image: testutil.FormFile(t, "cert.png", []byte("\x89PNG\r\n\x1a\n")),
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if entries, _ := os.ReadDir(certifUploadDir); len(entries) != 0 {
+		t.Errorf("uploads after a failed insert = %v, want none", entries)
+	}
+}
+
+func TestCertifReqValidation(t *testing.T) {
+	validate := validator.New()
+	image := testutil.FormFile(t, "cert.png", []byte("\x89PNG\r\n\x1a\n"))
+	empty, notUrl := "", "not a url"
+
+	tests := []struct {
+		name  string
+		input any
+		ok    bool
+	}{
+		{name: "create", input: CreateCertifReq{Name: "CKA", CertifLink: "https://example.com/cka", Image: image}, ok: true},
+		{name: "create without a file", input: CreateCertifReq{Name: "CKA"}},
+		{name: "create with a bad link", input: CreateCertifReq{Name: "CKA", CertifLink: notUrl, Image: image}},
+		{name: "update clears the link", input: UpdateCertifReq{CertifLink: &empty}, ok: true},
+		{name: "update with a bad link", input: UpdateCertifReq{CertifLink: &notUrl}},
+	}
+
+	for _, tt := range tests {
+		if err := validate.Struct(tt.input); (err == nil) != tt.ok {
+			t.Errorf("%s: validate error = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
@@ -4,17 +4,46 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/domains/skill"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
 )
 
 type Certificate struct {
-	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	Name       string
-	ImgUrl     string
-	CertifLink string
-	CreatedAt  time.Time `gorm:"autoCreateTime"`
-	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+	ID     uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Name   string
+	ImgUrl string
+	// set when the certificate was uploaded as a PDF, ImgUrl then holds its thumbnail
+	PdfUrl       *string
+	CertifLink   string
+	Issuer       string
+	IssuedAt     *time.Time
+	ExpiresAt    *time.Time
+	CredentialID *string
+	// free text list of skills, kept in the order they appear on the certificate
+	SkillsCovered common.StringList
+	// skills from the skill list this certificate proves
+	Skills       []skill.Skill `gorm:"many2many:certificate_skills;joinForeignKey:CertificateID;joinReferences:SkillID"`
+	Status       string
+	PublishAt    *time.Time
+	PreviewToken *string
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+	DeletedAt    gorm.DeletedAt
 }
 
 func (Certificate) TableName() string {
 	return "certificate"
 }
+
+type CertificateReminder struct {
+	CertificateID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	LeadDays      int       `gorm:"primaryKey"`
+	ExpiresAt     time.Time `gorm:"type:date;primaryKey"`
+	SentAt        time.Time `gorm:"autoCreateTime"`
+}
+
+func (CertificateReminder) TableName() string {
+	return "certificate_reminders"
+}
@@ -0,0 +1,152 @@
+package certif
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
+	fileutils "github.com/devanadindra/portfolio/back-end/utils/file"
+	"github.com/devanadindra/portfolio/back-end/utils/logger"
+	"github.com/devanadindra/portfolio/back-end/utils/thumbnail"
+)
+
+const certifUploadDir = "uploads/certificate"
+
+// saveCertifFile writes the uploaded file to disk and returns its public url.
+func saveCertifFile(ctx context.Context, certifID uuid.UUID, file *multipart.FileHeader) (string, error) {
+	if err := os.MkdirAll(certifUploadDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	filename, err := fileutils.GenerateMediaName(certifID.String())
+	if err != nil {
+		return "", err
+	}
+
+	filename += strings.ToLower(filepath.Ext(file.Filename))
+	url := "/" + certifUploadDir + "/" + filename
+	if err := fileutils.SaveMedia(ctx, file, filepath.Join(certifUploadDir, filename)); err != nil {
+		// the url is still returned so the caller can clean up a partially written file
+		return url, err
+	}
+
+	return url, nil
+}
+
+// saveCertifPdf stores the original PDF and renders its first page next to it as the
+// thumbnail. Both urls are returned even on error so the caller can clean them up.
+func (s *service) saveCertifPdf(ctx context.Context, certifID uuid.UUID, file *multipart.FileHeader) (pdfUrl string, thumbnailUrl string, err error) {
+	pdfUrl, err = saveCertifFile(ctx, certifID, file)
+	if err != nil {
+		return pdfUrl, "", err
+	}
+
+	thumbnailUrl = strings.TrimSuffix(pdfUrl, filepath.Ext(pdfUrl)) + ".png"
+	if err := s.pdfRenderer.RenderFirstPage(ctx, strings.TrimPrefix(pdfUrl, "/"), strings.TrimPrefix(thumbnailUrl, "/")); err != nil {
+		if errors.Is(err, thumbnail.ErrRendererUnavailable) {
+			// a server problem, the upload itself may be fine
+			return pdfUrl, thumbnailUrl, err
+		}
+		logger.Error(ctx, "%v", err)
+		return pdfUrl, thumbnailUrl, apierror.InvalidPdfFile(file.Filename)
+	}
+
+	return pdfUrl, thumbnailUrl, nil
+}
+
+// removeCertifFiles deletes files from disk, logging failures instead of returning them
+// because it is only used for cleanup after the database work has been decided.
+func removeCertifFiles(ctx context.Context, urls ...string) {
+	for _, url := range urls {
+		if err := fileutils.RemoveMedia(url); err != nil {
+			logger.Error(ctx, "%v", err)
+		}
+	}
+}
+
+// optionalDate maps a date form field sent empty, which binds to the zero time, to no date.
+func optionalDate(date *time.Time) *time.Time {
+	if date == nil || date.IsZero() {
+		return nil
+	}
+	return date
+}
+
+// validity reports whether a certificate expiring at expiresAt is still valid at now.
+// expires_at is a date, so a certificate stays valid through its expiry day. Certificates
+// without an expiry date never expire.
+func validity(expiresAt *time.Time, now time.Time, expiringSoon time.Duration) string {
+	today := now.UTC().Truncate(24 * time.Hour)
+
+	switch {
+	case expiresAt == nil:
+		return VALIDITY_VALID
+	case expiresAt.Before(today):
+		return VALIDITY_EXPIRED
+	case expiresAt.Before(today.Add(expiringSoon)):
+		return VALIDITY_EXPIRING_SOON
+	default:
+		return VALIDITY_VALID
+	}
+}
+
+// validityFilterScope keeps certificates in the given validity state, using the same
+// boundaries as validity.
+func validityFilterScope(state string, expiringSoon time.Duration) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		soon := today.Add(expiringSoon)
+		expiresAt := clause.Column{Table: clause.CurrentTable, Name: "expires_at"}
+
+		switch state {
+		case VALIDITY_EXPIRED:
+			return db.Where(clause.Lt{Column: expiresAt, Value: today})
+		case VALIDITY_EXPIRING_SOON:
+			return db.Where(clause.Gte{Column: expiresAt, Value: today}).
+				Where(clause.Lt{Column: expiresAt, Value: soon})
+		case VALIDITY_VALID:
+			return db.Where(clause.Or(
+				clause.Eq{Column: expiresAt, Value: nil},
+				clause.Gte{Column: expiresAt, Value: soon},
+			))
+		default:
+			return db
+		}
+	}
+}
+
+// issuerFilterScope matches the issuer case-insensitively.
+func issuerFilterScope(issuer string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		issuer = strings.TrimSpace(issuer)
+		if issuer == "" {
+			return db
+		}
+
+		return db.Where(clause.Expr{
+			SQL:  "LOWER(?) = LOWER(?)",
+			Vars: []any{clause.Column{Table: clause.CurrentTable, Name: "issuer"}, issuer},
+		})
+	}
+}
+
+// cleanSkillsCovered trims the submitted skills and drops empty and repeated ones.
+func cleanSkillsCovered(skills []string) common.StringList {
+	res := make(common.StringList, 0, len(skills))
+	for _, skill := range skills {
+		if skill = strings.TrimSpace(skill); skill != "" {
+			res = append(res, skill)
+		}
+	}
+	return common.UniqueArray(res)
+}
@@ -0,0 +1,91 @@
+package certif
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+func TestUpdateCertifReqClearsDates(t *testing.T) {
+	req := httptest.NewRequest("PATCH", "/", strings.NewReader("issued_at=2024-03-01&expires_at="))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var input UpdateCertifReq
+	if err := binding.Form.Bind(req, &input); err != nil {
+		t.Fatal(err)
+	}
+
+	if input.ExpiresAt == nil {
+		t.Fatal("expires_at sent empty was not bound, it could not be told from a missing field")
+	}
+	if got := optionalDate(input.ExpiresAt); got != nil {
+		t.Errorf("optionalDate(empty expires_at) = %v, want nil", got)
+	}
+
+	issued := optionalDate(input.IssuedAt)
+	if issued == nil || issued.Format("2006-01-02") != "2024-03-01" {
+		t.Errorf("optionalDate(issued_at) = %v, want 2024-03-01", issued)
+	}
+}
+
+func TestOptionalDate(t *testing.T) {
+	if got := optionalDate(nil); got != nil {
+		t.Errorf("optionalDate(nil) = %v, want nil", got)
+	}
+
+	date := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	if got := optionalDate(&date); got != &date {
+		t.Errorf("optionalDate(date) = %v, want %v", got, date)
+	}
+}
+
+func TestValidity(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	soon := 30 * 24 * time.Hour
+	// expires_at is a date and is read as midnight UTC
+	on := func(days int) *time.Time {
+		t := time.Date(2025, 6, 1+days, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		want      string
+	}{
+		{"no expiry", nil, VALIDITY_VALID},
+		{"expired yesterday", on(-1), VALIDITY_EXPIRED},
+		{"expires today", on(0), VALIDITY_EXPIRING_SOON},
+		{"expiring soon", on(10), VALIDITY_EXPIRING_SOON},
+		{"last day of the window", on(29), VALIDITY_EXPIRING_SOON},
+		{"valid", on(30), VALIDITY_VALID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validity(tt.expiresAt, now, soon); got != tt.want {
+				t.Errorf("validity() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// the list filter draws the same line as validity, a certificate expiring today is not expired
+func TestValidityFilterScopeExpired(t *testing.T) {
+	db, _ := testutil.NewMockDB(t)
+	today := time.Now().UTC().Truncate(24 * time.Hour).Format("2006-01-02 15:04:05")
+
+	got := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Scopes(validityFilterScope(VALIDITY_EXPIRED, time.Hour)).Find(&[]Certificate{})
+	})
+	want := `SELECT * FROM "certificate" WHERE "certificate"."expires_at" < '` + today + `' AND "certificate"."deleted_at" IS NULL`
+	if got != want {
+		t.Errorf("sql =\n%s\nwant\n%s", got, want)
+	}
+}
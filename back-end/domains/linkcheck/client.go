@@ -0,0 +1,19 @@
+package linkcheck
+
+import (
+	"net/http"
+
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+)
+
+// HTTPClient is the part of *http.Client used by the checker, tests can pass a client
+// pointing to an httptest server.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+func NewHTTPClient(conf *config.Config) HTTPClient {
+	return &http.Client{
+		Timeout: conf.LinkCheck.Timeout,
+	}
+}
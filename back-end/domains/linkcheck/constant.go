@@ -0,0 +1,6 @@
+package linkcheck
+
+const (
+	ENTITY_PROJECT     = "project"
+	ENTITY_CERTIFICATE = "certificate"
+)
@@ -0,0 +1,37 @@
+package linkcheck
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/respond"
+)
+
+type Handler interface {
+	GetBrokenLinks(ctx *gin.Context)
+}
+
+type handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service, validate *validator.Validate) Handler {
+	return &handler{
+		service:  service,
+		validate: validate,
+	}
+}
+
+func (h *handler) GetBrokenLinks(ctx *gin.Context) {
+	res, err := h.service.GetBrokenLinks(ctx)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
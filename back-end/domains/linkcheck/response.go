@@ -0,0 +1,18 @@
+package linkcheck
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LinkCheckRes struct {
+	EntityType  string    `json:"entity_type"`
+	EntityID    uuid.UUID `json:"entity_id"`
+	Name        string    `json:"name"`
+	Url         string    `json:"url"`
+	StatusCode  *int      `json:"status_code"`
+	Error       *string   `json:"error"`
+	RedirectUrl *string   `json:"redirect_url"`
+	CheckedAt   time.Time `json:"checked_at"`
+}
@@ -0,0 +1,247 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/domains/certif"
+	"github.com/devanadindra/portfolio/back-end/domains/project"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+	"github.com/devanadindra/portfolio/back-end/utils/scheduler"
+)
+
+type Service interface {
+	CheckLinks(ctx context.Context) error
+	LinkCheckJob() scheduler.Job
+	GetBrokenLinks(ctx context.Context) (*[]LinkCheckRes, error)
+}
+
+type service struct {
+	linkCheckConfig config.LinkCheck
+	dbSelector      *dbselector.DBService
+	VisitorsDB      *database.VisitorsDB
+	OwnerDB         *database.OwnerDB
+	client          HTTPClient
+}
+
+func NewService(config *config.Config, dbSelector *dbselector.DBService, VisitorsDB *database.VisitorsDB, OwnerDB *database.OwnerDB, client HTTPClient) Service {
+	return &service{
+		linkCheckConfig: config.LinkCheck,
+		dbSelector:      dbSelector,
+		VisitorsDB:      VisitorsDB,
+		OwnerDB:         OwnerDB,
+		client:          client,
+	}
+}
+
+type target struct {
+	entityType string
+	entityID   uuid.UUID
+	name       string
+	url        string
+}
+
+func (s *service) LinkCheckJob() scheduler.Job {
+	return scheduler.Job{
+		Name:     "link-check",
+		Interval: s.linkCheckConfig.Interval,
+		Run:      s.CheckLinks,
+	}
+}
+
+// CheckLinks requests every project url and certificate link and stores the outcome.
+// Results of links that no longer exist are removed at the end of the run.
+func (s *service) CheckLinks(ctx context.Context) error {
+	startedAt := time.Now()
+	db := s.OwnerDB.WithContext(ctx)
+
+	targets, err := findTargets(db)
+	if err != nil {
+		return err
+	}
+
+	concurrency := max(s.linkCheckConfig.Concurrency, 1)
+	sem := make(chan struct{}, concurrency)
+	results := make([]LinkCheck, len(targets))
+
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = s.check(ctx, t)
+		}()
+	}
+	wg.Wait()
+
+	// a cancelled run would report every remaining link as broken
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if len(results) > 0 {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "entity_type"}, {Name: "entity_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"url", "status_code", "error", "redirect_url", "broken", "checked_at"}),
+			}).CreateInBatches(&results, 100).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("checked_at < ?", startedAt).Delete(&LinkCheck{}).Error
+	})
+}
+
+func (s *service) GetBrokenLinks(ctx context.Context) (*[]LinkCheckRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []LinkCheck
+	if err := db.WithContext(ctx).
+		Where("broken").
+		Order("checked_at DESC").
+		Find(&checks).Error; err != nil {
+		return nil, err
+	}
+
+	targets, err := findTargets(db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, len(targets))
+	for _, t := range targets {
+		names[t.entityID] = t.name
+	}
+
+	res := make([]LinkCheckRes, 0, len(checks))
+	for _, c := range checks {
+		// the link was removed or trashed after the last run
+		name, ok := names[c.EntityID]
+		if !ok {
+			continue
+		}
+
+		res = append(res, LinkCheckRes{
+			EntityType:  c.EntityType,
+			EntityID:    c.EntityID,
+			Name:        name,
+			Url:         c.Url,
+			StatusCode:  c.StatusCode,
+			Error:       c.Error,
+			RedirectUrl: c.RedirectUrl,
+			CheckedAt:   c.CheckedAt,
+		})
+	}
+
+	return &res, nil
+}
+
+// check tries HEAD first and falls back to GET, many servers answer HEAD with an error
+// while the page itself works fine.
+func (s *service) check(ctx context.Context, t target) LinkCheck {
+	res := LinkCheck{
+		EntityType: t.entityType,
+		EntityID:   t.entityID,
+		Url:        t.url,
+		CheckedAt:  time.Now(),
+	}
+
+	parsed, err := url.Parse(t.url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		msg := "not an absolute http(s) url"
+		res.Error = &msg
+		res.Broken = true
+		return res
+	}
+
+	statusCode, finalUrl, err := s.request(ctx, http.MethodHead, t.url)
+	if err != nil || statusCode >= http.StatusBadRequest {
+		statusCode, finalUrl, err = s.request(ctx, http.MethodGet, t.url)
+	}
+
+	if err != nil {
+		msg := err.Error()
+		res.Error = &msg
+		res.Broken = true
+		return res
+	}
+
+	res.StatusCode = &statusCode
+	res.Broken = statusCode >= http.StatusBadRequest
+	if finalUrl != t.url {
+		res.RedirectUrl = &finalUrl
+	}
+
+	return res
+}
+
+func (s *service) request(ctx context.Context, method string, rawUrl string) (statusCode int, finalUrl string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, s.linkCheckConfig.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, rawUrl, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("User-Agent", s.linkCheckConfig.UserAgent)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("%s %s: %w", method, rawUrl, err)
+	}
+	defer resp.Body.Close()
+
+	// drain a little so the connection can be reused, the content itself is not needed
+	_, _ = io.CopyN(io.Discard, resp.Body, 64*1024)
+
+	finalUrl = rawUrl
+	if resp.Request != nil && resp.Request.URL != nil {
+		finalUrl = resp.Request.URL.String()
+	}
+
+	return resp.StatusCode, finalUrl, nil
+}
+
+// findTargets lists every non empty project url and certificate link, trashed rows excluded.
+func findTargets(db *gorm.DB) ([]target, error) {
+	var projects []project.Projects
+	if err := db.Select("id", "name", "project_url").
+		Where("project_url <> ''").
+		Find(&projects).Error; err != nil {
+		return nil, err
+	}
+
+	var certifs []certif.Certificate
+	if err := db.Select("id", "name", "certif_link").
+		Where("certif_link <> ''").
+		Find(&certifs).Error; err != nil {
+		return nil, err
+	}
+
+	targets := make([]target, 0, len(projects)+len(certifs))
+	for _, p := range projects {
+		targets = append(targets, target{entityType: ENTITY_PROJECT, entityID: p.ID, name: p.Name, url: p.ProjectUrl})
+	}
+	for _, c := range certifs {
+		targets = append(targets, target{entityType: ENTITY_CERTIFICATE, entityID: c.ID, name: c.Name, url: c.CertifLink})
+	}
+
+	return targets, nil
+}
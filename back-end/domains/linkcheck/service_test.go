@@ -0,0 +1,176 @@
+package linkcheck
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+// capture is a sqlmock argument that accepts anything and keeps the value.
+type capture struct {
+	values *[]driver.Value
+}
+
+func (c capture) Match(v driver.Value) bool {
+	*c.values = append(*c.values, v)
+	return true
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestService(t *testing.T, srv *httptest.Server) (*service, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock := testutil.NewMockDB(t)
+
+	return &service{
+		linkCheckConfig: config.LinkCheck{Timeout: 200 * time.Millisecond, Concurrency: 2, UserAgent: "test"},
+		OwnerDB:         &database.OwnerDB{DB: db},
+		client:          srv.Client(),
+	}, mock
+}
+
+func TestCheck(t *testing.T) {
+	srv := newTestServer(t)
+	s, _ := newTestService(t, srv)
+
+	tests := []struct {
+		name       string
+		path       string
+		statusCode int
+		broken     bool
+		redirect   string
+		failed     bool
+	}{
+		{name: "ok", path: "/ok", statusCode: http.StatusOK},
+		{name: "not found", path: "/missing", statusCode: http.StatusNotFound, broken: true},
+		{name: "redirect", path: "/old", statusCode: http.StatusOK, redirect: srv.URL + "/ok"},
+		{name: "timeout", path: "/slow", broken: true, failed: true},
+		{name: "head not allowed", path: "/no-head", statusCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := s.check(context.Background(), target{entityType: ENTITY_PROJECT, entityID: uuid.New(), url: srv.URL + tt.path})
+
+			if res.Broken != tt.broken {
+				t.Errorf("broken = %v, want %v", res.Broken, tt.broken)
+			}
+			if tt.failed {
+				if res.Error == nil || res.StatusCode != nil {
+					t.Errorf("error = %v, status = %v, want an error without status", res.Error, res.StatusCode)
+				}
+				return
+			}
+			if res.StatusCode == nil || *res.StatusCode != tt.statusCode {
+				t.Errorf("status = %v, want %d", res.StatusCode, tt.statusCode)
+			}
+			if tt.redirect == "" && res.RedirectUrl != nil {
+				t.Errorf("redirect = %q, want none", *res.RedirectUrl)
+			}
+			if tt.redirect != "" && (res.RedirectUrl == nil || *res.RedirectUrl != tt.redirect) {
+				t.Errorf("redirect = %v, want %q", res.RedirectUrl, tt.redirect)
+			}
+		})
+	}
+}
+
+func TestCheckInvalidUrl(t *testing.T) {
+	s, _ := newTestService(t, newTestServer(t))
+
+	for _, raw := range []string{"example.com/page", "ftp://example.com", "https://"} {
+		res := s.check(context.Background(), target{url: raw})
+		if !res.Broken || res.Error == nil {
+			t.Errorf("%q: broken = %v, error = %v, want broken with an error", raw, res.Broken, res.Error)
+		}
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	srv := newTestServer(t)
+	s, mock := newTestService(t, srv)
+
+	paths := []string{"/ok", "/missing", "/old", "/slow", "/no-head"}
+	projects := sqlmock.NewRows([]string{"id", "name", "project_url"})
+	for _, path := range paths {
+		projects.AddRow(uuid.New(), path, srv.URL+path)
+	}
+
+	var args []driver.Value
+	insertArgs := make([]driver.Value, 0, len(paths)*8)
+	for range cap(insertArgs) {
+		insertArgs = append(insertArgs, capture{values: &args})
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","name","project_url" FROM "projects" WHERE project_url <> ''`)).
+		WillReturnRows(projects)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","name","certif_link" FROM "certificate" WHERE certif_link <> ''`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "certif_link"}))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "link_checks"`)).
+		WithArgs(insertArgs...).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "link_checks" WHERE checked_at < $1`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if err := s.CheckLinks(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	// entity_type, entity_id, url, status_code, error, redirect_url, broken, checked_at
+	wantBroken := map[string]bool{"/ok": false, "/missing": true, "/old": false, "/slow": true, "/no-head": false}
+	for i, path := range paths {
+		row := args[i*8 : i*8+8]
+		if row[2] != srv.URL+path {
+			t.Fatalf("row %d url = %v, want %s", i, row[2], srv.URL+path)
+		}
+		if row[6] != wantBroken[path] {
+			t.Errorf("%s: broken = %v, want %v", path, row[6], wantBroken[path])
+		}
+	}
+}
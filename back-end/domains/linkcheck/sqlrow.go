@@ -0,0 +1,23 @@
+package linkcheck
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LinkCheck struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	EntityType  string
+	EntityID    uuid.UUID `gorm:"type:uuid"`
+	Url         string
+	StatusCode  *int
+	Error       *string
+	RedirectUrl *string
+	Broken      bool
+	CheckedAt   time.Time
+}
+
+func (LinkCheck) TableName() string {
+	return "link_checks"
+}
@@ -0,0 +1,5 @@
+package notification
+
+const (
+	TYPE_CERTIFICATE_EXPIRY = "certificate_expiry"
+)
@@ -0,0 +1,69 @@
+package notification
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	"github.com/devanadindra/portfolio/back-end/utils/respond"
+)
+
+type Handler interface {
+	GetNotifications(ctx *gin.Context)
+	MarkAsRead(ctx *gin.Context)
+}
+
+type handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service, validate *validator.Validate) Handler {
+	return &handler{
+		service:  service,
+		validate: validate,
+	}
+}
+
+func (h *handler) GetNotifications(ctx *gin.Context) {
+	filter, err := common.GetMetaData(ctx, h.validate, "created_at")
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	req := GetNotificationsReq{
+		Page:   filter.Page,
+		Limit:  filter.Limit,
+		Unread: ctx.Query(constants.QUERY_PARAMS_UNREAD) == "true",
+	}
+
+	notifications, total, err := h.service.GetNotifications(ctx, req, *filter)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{
+		"data":  notifications,
+		"total": total,
+		"page":  filter.Page,
+		"limit": filter.Limit,
+	})
+}
+
+func (h *handler) MarkAsRead(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	res, err := h.service.MarkAsRead(ctx, id)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
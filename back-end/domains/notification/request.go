@@ -0,0 +1,7 @@
+package notification
+
+type GetNotificationsReq struct {
+	Page   int64
+	Limit  int64
+	Unread bool
+}
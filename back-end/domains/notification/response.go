@@ -0,0 +1,31 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRes struct {
+	ID         uuid.UUID  `json:"id"`
+	Type       string     `json:"type"`
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	EntityType *string    `json:"entity_type"`
+	EntityID   *uuid.UUID `json:"entity_id"`
+	ReadAt     *time.Time `json:"read_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func ToNotificationRes(n Notification) NotificationRes {
+	return NotificationRes{
+		ID:         n.ID,
+		Type:       n.Type,
+		Title:      n.Title,
+		Body:       n.Body,
+		EntityType: n.EntityType,
+		EntityID:   n.EntityID,
+		ReadAt:     n.ReadAt,
+		CreatedAt:  n.CreatedAt,
+	}
+}
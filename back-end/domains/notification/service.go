@@ -0,0 +1,104 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+)
+
+type Service interface {
+	GetNotifications(ctx context.Context, input GetNotificationsReq, filter constants.FilterReq) (*[]NotificationRes, int64, error)
+	MarkAsRead(ctx context.Context, notificationId string) (*NotificationRes, error)
+}
+
+type service struct {
+	authConfig config.Auth
+	dbSelector *dbselector.DBService
+	VisitorsDB *database.VisitorsDB
+	OwnerDB    *database.OwnerDB
+}
+
+func NewService(config *config.Config, dbSelector *dbselector.DBService, VisitorsDB *database.VisitorsDB, OwnerDB *database.OwnerDB) Service {
+	return &service{
+		authConfig: config.Auth,
+		dbSelector: dbSelector,
+		VisitorsDB: VisitorsDB,
+		OwnerDB:    OwnerDB,
+	}
+}
+
+func (s *service) GetNotifications(ctx context.Context, input GetNotificationsReq, filter constants.FilterReq) (*[]NotificationRes, int64, error) {
+	var total int64
+	var notifications []Notification
+
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := db.WithContext(ctx).
+		Model(&Notification{}).
+		Scopes(common.FilterScope(filter, "title", "body"))
+	if input.Unread {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Scopes(common.PaginateScope(filter)).
+		Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	res := make([]NotificationRes, len(notifications))
+	for i, n := range notifications {
+		res[i] = ToNotificationRes(n)
+	}
+
+	return &res, total, nil
+}
+
+func (s *service) MarkAsRead(ctx context.Context, notificationId string) (*NotificationRes, error) {
+	id, err := uuid.Parse(notificationId)
+	if err != nil {
+		return nil, apierror.InvalidNotificationId()
+	}
+
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var notification Notification
+	if err := db.WithContext(ctx).First(&notification, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apierror.NotificationNotFound(notificationId)
+		}
+		return nil, apierror.FromErr(err)
+	}
+
+	if notification.ReadAt == nil {
+		now := time.Now()
+		notification.ReadAt = &now
+		if err := db.WithContext(ctx).Model(&notification).
+			Update("read_at", now).Error; err != nil {
+			return nil, apierror.FromErr(err)
+		}
+	}
+
+	res := ToNotificationRes(notification)
+	return &res, nil
+}
@@ -0,0 +1,23 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Notification struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Type       string
+	Title      string
+	Body       string
+	EntityType *string
+	EntityID   *uuid.UUID `gorm:"type:uuid"`
+	ReadAt     *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,9 @@
+package project
+
+const (
+	TAG_MODE_AND = "and"
+	TAG_MODE_OR  = "or"
+)
+
+// values accepted by the include query param of the project list
+const INCLUDE_IMAGES = "images"
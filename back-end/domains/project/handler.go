@@ -2,12 +2,16 @@ package project
 
 import (
 	"net/http"
+	"strings"
 
 	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
 	"github.com/devanadindra/portfolio/back-end/utils/common"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	fileutils "github.com/devanadindra/portfolio/back-end/utils/file"
 	"github.com/devanadindra/portfolio/back-end/utils/respond"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 )
 
 type Handler interface {
@@ -15,6 +19,19 @@ type Handler interface {
 	DeleteProject(ctx *gin.Context)
 	GetAllProjects(ctx *gin.Context)
 	GetProjectById(ctx *gin.Context)
+	CreateProject(ctx *gin.Context)
+	UpdateProject(ctx *gin.Context)
+	AddProjectImages(ctx *gin.Context)
+	DeleteProjectImage(ctx *gin.Context)
+	SetProjectCover(ctx *gin.Context)
+	ReorderProjectImages(ctx *gin.Context)
+	SetProjectTags(ctx *gin.Context)
+	UpdateProjectStatus(ctx *gin.Context)
+	CreatePreviewToken(ctx *gin.Context)
+	DeletePreviewToken(ctx *gin.Context)
+	GetFeaturedProjects(ctx *gin.Context)
+	ReorderProjects(ctx *gin.Context)
+	SetProjectFeatured(ctx *gin.Context)
 }
 
 type handler struct {
@@ -48,19 +65,28 @@ func (h *handler) DeleteProject(ctx *gin.Context) {
 		return
 	}
 
-	respond.Success(ctx, http.StatusCreated, gin.H{"message": "kamus and video deleted successfully"})
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "project moved to trash"})
 }
 
 func (h *handler) GetAllProjects(ctx *gin.Context) {
-	filter, err := common.GetMetaData(ctx, h.validate, "created_at")
+	filter, err := common.GetMetaData(ctx, h.validate, "created_at", "position")
 	if err != nil {
 		respond.Error(ctx, apierror.FromErr(err))
 		return
 	}
 
 	req := GetAllProjectsReq{
-		Page:  filter.Page,
-		Limit: filter.Limit,
+		Page:    filter.Page,
+		Limit:   filter.Limit,
+		Tags:    splitQueryValues(ctx.QueryArray(constants.QUERY_PARAMS_TAG)),
+		TagMode: strings.ToLower(ctx.DefaultQuery(constants.QUERY_PARAMS_TAG_MODE, TAG_MODE_OR)),
+		SkillID: ctx.Query(constants.QUERY_PARAMS_SKILL),
+		Include: splitQueryValues(ctx.QueryArray(constants.QUERY_PARAMS_INCLUDE)),
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
 	}
 
 	kamusList, total, err := h.service.GetAllProjects(ctx, req, *filter)
@@ -78,13 +104,281 @@ func (h *handler) GetAllProjects(ctx *gin.Context) {
 }
 
 func (h *handler) GetProjectById(ctx *gin.Context) {
-	arti := ctx.Param("arti")
+	idOrSlug := ctx.Param("idOrSlug")
+
+	res, err := h.service.GetProjectById(ctx, idOrSlug, ctx.Query(constants.QUERY_PARAMS_PREVIEW))
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	// resolved through an old slug, send the client to the current one
+	if _, parseErr := uuid.Parse(idOrSlug); parseErr != nil && idOrSlug != res.Slug {
+		location := strings.TrimSuffix(ctx.Request.URL.Path, idOrSlug) + res.Slug
+		if ctx.Request.URL.RawQuery != "" {
+			location += "?" + ctx.Request.URL.RawQuery
+		}
+		ctx.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) CreateProject(ctx *gin.Context) {
+	var input CreateProjectReq
+	if err := ctx.ShouldBind(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	for _, file := range input.Images {
+		if !fileutils.IsValidImage(file) {
+			respond.Error(ctx, apierror.InvalidImageFile(file.Filename))
+			return
+		}
+	}
 
-	kamus, err := h.service.GetProjectById(ctx, arti)
+	res, err := h.service.CreateProject(ctx, input)
 	if err != nil {
 		respond.Error(ctx, apierror.FromErr(err))
 		return
 	}
 
-	respond.Success(ctx, http.StatusCreated, kamus)
+	respond.Success(ctx, http.StatusCreated, res)
+}
+
+func (h *handler) UpdateProject(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input UpdateProjectReq
+	if err := ctx.ShouldBind(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	for _, file := range input.Images {
+		if !fileutils.IsValidImage(file) {
+			respond.Error(ctx, apierror.InvalidImageFile(file.Filename))
+			return
+		}
+	}
+
+	res, err := h.service.UpdateProject(ctx, id, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) AddProjectImages(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input AddProjectImagesReq
+	if err := ctx.ShouldBind(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	for _, file := range input.Images {
+		if !fileutils.IsValidImage(file) {
+			respond.Error(ctx, apierror.InvalidImageFile(file.Filename))
+			return
+		}
+	}
+
+	res, err := h.service.AddProjectImages(ctx, id, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusCreated, res)
+}
+
+func (h *handler) DeleteProjectImage(ctx *gin.Context) {
+	id := ctx.Param("id")
+	imageId := ctx.Param("imageId")
+
+	if err := h.service.DeleteProjectImage(ctx, id, imageId); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "project image deleted successfully"})
+}
+
+func (h *handler) SetProjectCover(ctx *gin.Context) {
+	id := ctx.Param("id")
+	imageId := ctx.Param("imageId")
+
+	res, err := h.service.SetProjectCover(ctx, id, imageId)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) ReorderProjectImages(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input ReorderProjectImagesReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	res, err := h.service.ReorderProjectImages(ctx, id, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) SetProjectTags(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input SetProjectTagsReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	res, err := h.service.SetProjectTags(ctx, id, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) UpdateProjectStatus(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input UpdateProjectStatusReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	res, err := h.service.UpdateProjectStatus(ctx, id, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) CreatePreviewToken(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	res, err := h.service.CreatePreviewToken(ctx, id)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusCreated, res)
+}
+
+func (h *handler) DeletePreviewToken(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := h.service.DeletePreviewToken(ctx, id); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "preview link revoked successfully"})
+}
+
+func (h *handler) GetFeaturedProjects(ctx *gin.Context) {
+	res, err := h.service.GetFeaturedProjects(ctx)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) ReorderProjects(ctx *gin.Context) {
+	var input ReorderProjectsReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	if err := h.service.ReorderProjects(ctx, input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "projects reordered successfully"})
+}
+
+func (h *handler) SetProjectFeatured(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input SetProjectFeaturedReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	res, err := h.service.SetProjectFeatured(ctx, id, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
 }
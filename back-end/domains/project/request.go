@@ -1,6 +1,11 @@
 package project
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type KamusReq struct {
 	Arti     string                `form:"arti" binding:"required"`
@@ -13,6 +18,52 @@ type DeleteKamusReq struct {
 }
 
 type GetAllProjectsReq struct {
-	Page  int64
-	Limit int64
+	Page    int64
+	Limit   int64
+	Tags    []string
+	TagMode string   `validate:"omitempty,oneof=and or"`
+	SkillID string   `validate:"omitempty,uuid"`
+	Include []string `validate:"omitempty,dive,oneof=images"`
+}
+
+type CreateProjectReq struct {
+	Name        string                  `form:"name" validate:"required,max=255"`
+	Description string                  `form:"description"`
+	ProjectUrl  string                  `form:"project_url" validate:"omitempty,url,max=255"`
+	Images      []*multipart.FileHeader `form:"images"`
+	TagIDs      []string                `form:"tag_ids" validate:"omitempty,dive,uuid"`
+	Status      string                  `form:"status" validate:"omitempty,oneof=draft published archived"`
+	PublishAt   *time.Time              `form:"publish_at"`
+}
+
+type UpdateProjectReq struct {
+	Name        *string                 `form:"name" validate:"omitempty,min=1,max=255"`
+	Description *string                 `form:"description"`
+	ProjectUrl  *string                 `form:"project_url" validate:"omitempty,url,max=255"`
+	Images      []*multipart.FileHeader `form:"images"`
+}
+
+type AddProjectImagesReq struct {
+	Images []*multipart.FileHeader `form:"images" validate:"required,gt=0"`
+}
+
+type ReorderProjectImagesReq struct {
+	ImageIDs []uuid.UUID `json:"image_ids" validate:"required,gt=0"`
+}
+
+type SetProjectTagsReq struct {
+	TagIDs []string `json:"tag_ids" validate:"required,dive,uuid"`
+}
+
+type ReorderProjectsReq struct {
+	ProjectIDs []uuid.UUID `json:"project_ids" validate:"required,gt=0"`
+}
+
+type SetProjectFeaturedReq struct {
+	Featured *bool `json:"featured" validate:"required"`
+}
+
+type UpdateProjectStatusReq struct {
+	Status    string     `json:"status" validate:"required,oneof=draft published archived"`
+	PublishAt *time.Time `json:"publish_at"`
 }
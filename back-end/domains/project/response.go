@@ -1,15 +1,37 @@
 package project
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/devanadindra/portfolio/back-end/domains/skill"
+	"github.com/devanadindra/portfolio/back-end/domains/tag"
+)
 
 type ProjectRes struct {
+	ID          uuid.UUID
 	Name        string
+	Slug        string
 	Description string
 	ProjectUrl  string
+	Status      string
+	PublishAt   *time.Time
+	Position    int
+	Featured    bool
 	Images      []ProjectImagesRes
+	Tags        []tag.TagRes
+	Skills      []skill.SkillSummaryRes
 }
 
 type ProjectImagesRes struct {
+	ID        uuid.UUID
 	ProjectID uuid.UUID
 	ImgUrl    string
+	Position  int
+	IsCover   bool
+}
+
+type PreviewTokenRes struct {
+	PreviewToken string
 }
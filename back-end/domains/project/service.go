@@ -3,23 +3,39 @@ package project
 import (
 	"context"
 	"errors"
-	"fmt"
-	"os"
-	"strings"
+	"slices"
+	"time"
 
 	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/domains/skill"
 	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
 	"github.com/devanadindra/portfolio/back-end/utils/config"
 	"github.com/devanadindra/portfolio/back-end/utils/constants"
 	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Service interface {
 	GetProjects(ctx context.Context) (*[]ProjectRes, error)
 	DeleteProject(ctx context.Context, projectId string) error
 	GetAllProjects(ctx context.Context, input GetAllProjectsReq, filter constants.FilterReq) (*[]ProjectRes, int64, error)
-	GetProjectById(ctx context.Context, projectId string) (*ProjectRes, error)
+	GetProjectById(ctx context.Context, idOrSlug string, previewToken string) (*ProjectRes, error)
+	CreateProject(ctx context.Context, input CreateProjectReq) (*ProjectRes, error)
+	UpdateProject(ctx context.Context, projectId string, input UpdateProjectReq) (*ProjectRes, error)
+	AddProjectImages(ctx context.Context, projectId string, input AddProjectImagesReq) (*ProjectRes, error)
+	DeleteProjectImage(ctx context.Context, projectId string, imageId string) error
+	SetProjectCover(ctx context.Context, projectId string, imageId string) (*ProjectRes, error)
+	ReorderProjectImages(ctx context.Context, projectId string, input ReorderProjectImagesReq) (*ProjectRes, error)
+	SetProjectTags(ctx context.Context, projectId string, input SetProjectTagsReq) (*ProjectRes, error)
+	UpdateProjectStatus(ctx context.Context, projectId string, input UpdateProjectStatusReq) (*ProjectRes, error)
+	CreatePreviewToken(ctx context.Context, projectId string) (*PreviewTokenRes, error)
+	DeletePreviewToken(ctx context.Context, projectId string) error
+	GetFeaturedProjects(ctx context.Context) (*[]ProjectRes, error)
+	ReorderProjects(ctx context.Context, input ReorderProjectsReq) error
+	SetProjectFeatured(ctx context.Context, projectId string, input SetProjectFeaturedReq) (*ProjectRes, error)
 }
 
 type service struct {
@@ -46,7 +62,9 @@ func (s *service) GetProjects(ctx context.Context) (*[]ProjectRes, error) {
 
 	var projectList []Projects
 	err = db.WithContext(ctx).
-		Preload("Images").
+		Preload("Images", orderedImages).
+		Preload("Tags", orderedTags).
+		Preload("Skills", skill.OrderedSkills).
 		Find(&projectList).Error
 	if err != nil {
 		return nil, err
@@ -54,20 +72,7 @@ func (s *service) GetProjects(ctx context.Context) (*[]ProjectRes, error) {
 
 	res := make([]ProjectRes, len(projectList))
 	for i, p := range projectList {
-		images := make([]ProjectImagesRes, len(p.Images))
-		for j, img := range p.Images {
-			images[j] = ProjectImagesRes{
-				ProjectID: img.ProjectID,
-				ImgUrl:    img.ImgUrl,
-			}
-		}
-
-		res[i] = ProjectRes{
-			Name:        p.Name,
-			Description: p.Description,
-			ProjectUrl:  p.ProjectUrl,
-			Images:      images,
-		}
+		res[i] = *toProjectRes(p)
 	}
 
 	return &res, nil
@@ -79,28 +84,24 @@ func (s *service) DeleteProject(ctx context.Context, projectId string) error {
 		return err
 	}
 
+	// soft delete only, files stay on disk until the trash is purged
 	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		var project Projects
-
-		if err := tx.Preload("Images").
-			First(&project, "id = ?", projectId).Error; err != nil {
-			return fmt.Errorf("project not found: %w", err)
+		project, err := findProjectWithImages(tx, projectId)
+		if err != nil {
+			return err
 		}
 
-		for _, img := range project.Images {
-			cleanPath := strings.TrimPrefix(img.ImgUrl, "/")
-
-			if err := os.Remove(cleanPath); err != nil && !os.IsNotExist(err) {
-				return fmt.Errorf("failed delete file %s: %w", cleanPath, err)
-			}
-		}
+		// images share the project timestamp so a restore brings back exactly this batch
+		deletedAt := time.Now()
 
-		if err := tx.Where("project_id = ?", project.ID).
-			Delete(&ProjectImages{}).Error; err != nil {
+		if err := tx.Model(&ProjectImages{}).
+			Where("project_id = ?", project.ID).
+			Update("deleted_at", deletedAt).Error; err != nil {
 			return err
 		}
 
-		if err := tx.Delete(&project).Error; err != nil {
+		if err := tx.Model(&project).
+			Update("deleted_at", deletedAt).Error; err != nil {
 			return err
 		}
 
@@ -117,60 +118,571 @@ func (s *service) GetAllProjects(ctx context.Context, input GetAllProjectsReq, f
 		return nil, 0, err
 	}
 
-	query := db.WithContext(ctx).Model(&Projects{})
+	query := db.WithContext(ctx).
+		Model(&Projects{}).
+		Scopes(
+			s.dbSelector.VisibleScope(ctx),
+			common.FilterScope(filter, "name", "description"),
+			tagFilterScope(input.Tags, input.TagMode),
+			skillFilterScope(input.SkillID),
+		)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	offset := (filter.Page - 1) * filter.Limit
-
 	if err := query.
-		Order(fmt.Sprintf("%s %s", filter.OrderBy, filter.SortOrder)).
-		Limit(int(filter.Limit)).
-		Offset(int(offset)).
+		Scopes(common.PaginateScope(filter)).
+		Preload("Tags", orderedTags).
+		Preload("Skills", skill.OrderedSkills).
 		Find(&projectList).Error; err != nil {
 		return nil, 0, err
 	}
 
+	if err := loadProjectImages(db.WithContext(ctx), projectList, slices.Contains(input.Include, INCLUDE_IMAGES)); err != nil {
+		return nil, 0, err
+	}
+
 	res := make([]ProjectRes, len(projectList))
 	for i, p := range projectList {
-		images := make([]ProjectImagesRes, len(p.Images))
-		for j, img := range p.Images {
-			images[j] = ProjectImagesRes{
-				ProjectID: img.ProjectID,
-				ImgUrl:    img.ImgUrl,
+		res[i] = *toProjectRes(p)
+	}
+
+	return &res, total, nil
+}
+
+func (s *service) GetProjectById(ctx context.Context, idOrSlug string, previewToken string) (*ProjectRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.WithContext(ctx).
+		Preload("Images", orderedImages).
+		Preload("Tags", orderedTags).
+		Preload("Skills", skill.OrderedSkills)
+
+	var project Projects
+	if id, parseErr := uuid.Parse(idOrSlug); parseErr == nil {
+		err = query.First(&project, "id = ?", id).Error
+	} else {
+		err = query.First(&project, "slug = ?", idOrSlug).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			var alias ProjectSlugAlias
+			if err = db.WithContext(ctx).First(&alias, "slug = ?", idOrSlug).Error; err == nil {
+				err = query.First(&project, "id = ?", alias.ProjectID).Error
 			}
 		}
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apierror.ProjectNotFound(idOrSlug)
+		}
+		return nil, err
+	}
 
-		res[i] = ProjectRes{
-			Name:        p.Name,
-			Description: p.Description,
-			ProjectUrl:  p.ProjectUrl,
-			Images:      images,
+	// unpublished projects look missing unless the owner asks or a valid preview link is used
+	if !s.dbSelector.IsOwner(ctx) && !common.IsPublished(project.Status, project.PublishAt) {
+		if previewToken == "" || project.PreviewToken == nil || !common.TokenEquals(*project.PreviewToken, previewToken) {
+			return nil, apierror.ProjectNotFound(idOrSlug)
 		}
 	}
 
-	return &res, total, nil
+	return toProjectRes(project), nil
+}
+
+func (s *service) CreateProject(ctx context.Context, input CreateProjectReq) (*ProjectRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project := Projects{
+		ID:          uuid.New(),
+		Name:        input.Name,
+		Description: input.Description,
+		ProjectUrl:  input.ProjectUrl,
+		Status:      common.Ternary(input.Status != "", input.Status, constants.STATUS_PUBLISHED),
+		PublishAt:   input.PublishAt,
+	}
+
+	var savedUrls []string
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		project.Slug, err = uniqueSlug(tx, project.Name, project.ID)
+		if err != nil {
+			return err
+		}
+
+		// new projects go on top, matching the newest first order the list started with
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).
+			Unscoped().Model(&Projects{}).
+			Update("position", gorm.Expr("position + 1")).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&project).Error; err != nil {
+			return err
+		}
+
+		images, urls, err := saveProjectImages(ctx, project.ID, 0, input.Images)
+		savedUrls = urls
+		if err != nil {
+			return err
+		}
+
+		if len(images) > 0 {
+			if err := tx.Create(&images).Error; err != nil {
+				return err
+			}
+		}
+		project.Images = images
+
+		if len(input.TagIDs) > 0 {
+			project.Tags, err = setProjectTags(tx, project.ID, input.TagIDs)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		removeProjectFiles(ctx, savedUrls)
+		return nil, apierror.FromErr(err)
+	}
+
+	return toProjectRes(project), nil
 }
 
-func (s *service) GetProjectById(ctx context.Context, projectId string) (*ProjectRes, error) {
+func (s *service) UpdateProject(ctx context.Context, projectId string, input UpdateProjectReq) (*ProjectRes, error) {
 	db, err := s.dbSelector.GetDBByRole(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var project Projects
-	if err := db.WithContext(ctx).First(&project, "arti = ?", projectId).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, apierror.ProjectNotFound(projectId)
+	var savedUrls []string
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		project, err = findProjectWithImages(tx, projectId)
+		if err != nil {
+			return err
+		}
+
+		if input.Name != nil && *input.Name != project.Name {
+			project.Name = *input.Name
+			if err := renameSlug(tx, &project); err != nil {
+				return err
+			}
+		}
+		if input.Description != nil {
+			project.Description = *input.Description
+		}
+		if input.ProjectUrl != nil {
+			project.ProjectUrl = *input.ProjectUrl
+		}
+
+		if err := tx.Omit(clause.Associations).Save(&project).Error; err != nil {
+			return err
+		}
+
+		// new images replace the whole gallery, the old ones go to the trash
+		if len(input.Images) == 0 {
+			return nil
 		}
+
+		images, urls, err := saveProjectImages(ctx, project.ID, 0, input.Images)
+		savedUrls = urls
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("project_id = ?", project.ID).
+			Delete(&ProjectImages{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&images).Error; err != nil {
+			return err
+		}
+		project.Images = images
+
+		return nil
+	})
+	if err != nil {
+		removeProjectFiles(ctx, savedUrls)
+		return nil, apierror.FromErr(err)
+	}
+
+	return toProjectRes(project), nil
+}
+
+func (s *service) AddProjectImages(ctx context.Context, projectId string, input AddProjectImagesReq) (*ProjectRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	return &ProjectRes{
-		Name:        project.Name,
-		Description: project.Description,
-		ProjectUrl:  project.ProjectUrl,
-	}, nil
+	var project Projects
+	var savedUrls []string
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		project, err = findProjectWithImages(tx, projectId)
+		if err != nil {
+			return err
+		}
+
+		nextPosition := 0
+		if len(project.Images) > 0 {
+			nextPosition = project.Images[len(project.Images)-1].Position + 1
+		}
+
+		images, urls, err := saveProjectImages(ctx, project.ID, nextPosition, input.Images)
+		savedUrls = urls
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(&images).Error; err != nil {
+			return err
+		}
+		project.Images = append(project.Images, images...)
+
+		return nil
+	})
+	if err != nil {
+		removeProjectFiles(ctx, savedUrls)
+		return nil, apierror.FromErr(err)
+	}
+
+	return toProjectRes(project), nil
+}
+
+func (s *service) DeleteProjectImage(ctx context.Context, projectId string, imageId string) error {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		image, err := findProjectImage(tx, projectId, imageId)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&image).
+			Select("deleted_at", "is_cover").
+			Updates(map[string]any{"deleted_at": time.Now(), "is_cover": false}).Error
+	})
+	if err != nil {
+		return apierror.FromErr(err)
+	}
+
+	return nil
+}
+
+func (s *service) SetProjectCover(ctx context.Context, projectId string, imageId string) (*ProjectRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var project Projects
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		image, err := findProjectImage(tx, projectId, imageId)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&ProjectImages{}).
+			Where("project_id = ? AND is_cover", image.ProjectID).
+			Update("is_cover", false).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&image).Update("is_cover", true).Error; err != nil {
+			return err
+		}
+
+		project, err = findProjectWithImages(tx, projectId)
+		return err
+	})
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return toProjectRes(project), nil
+}
+
+func (s *service) ReorderProjectImages(ctx context.Context, projectId string, input ReorderProjectImagesReq) (*ProjectRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var project Projects
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		project, err = findProjectWithImages(tx, projectId)
+		if err != nil {
+			return err
+		}
+
+		existing := make(map[uuid.UUID]bool, len(project.Images))
+		for _, img := range project.Images {
+			existing[img.ID] = true
+		}
+
+		if len(input.ImageIDs) != len(existing) {
+			return apierror.InvalidImageOrder()
+		}
+		for _, id := range input.ImageIDs {
+			if !existing[id] {
+				return apierror.InvalidImageOrder()
+			}
+			delete(existing, id)
+		}
+
+		for position, id := range input.ImageIDs {
+			if err := tx.Model(&ProjectImages{}).
+				Where("id = ?", id).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+
+		project, err = findProjectWithImages(tx, projectId)
+		return err
+	})
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return toProjectRes(project), nil
+}
+
+func (s *service) SetProjectTags(ctx context.Context, projectId string, input SetProjectTagsReq) (*ProjectRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var project Projects
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		project, err = findProjectWithImages(tx, projectId)
+		if err != nil {
+			return err
+		}
+
+		project.Tags, err = setProjectTags(tx, project.ID, input.TagIDs)
+		return err
+	})
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return toProjectRes(project), nil
+}
+
+func (s *service) UpdateProjectStatus(ctx context.Context, projectId string, input UpdateProjectStatusReq) (*ProjectRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := findProjectWithImages(db.WithContext(ctx), projectId)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	project.Status = input.Status
+	project.PublishAt = input.PublishAt
+
+	if err := db.WithContext(ctx).Model(&project).
+		Select("status", "publish_at").
+		Updates(&project).Error; err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return toProjectRes(project), nil
+}
+
+func (s *service) CreatePreviewToken(ctx context.Context, projectId string) (*PreviewTokenRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := findProjectWithImages(db.WithContext(ctx), projectId)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	token, err := common.GenerateToken(32)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	if err := db.WithContext(ctx).Model(&project).
+		Update("preview_token", token).Error; err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return &PreviewTokenRes{PreviewToken: token}, nil
+}
+
+func (s *service) DeletePreviewToken(ctx context.Context, projectId string) error {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return err
+	}
+
+	project, err := findProjectWithImages(db.WithContext(ctx), projectId)
+	if err != nil {
+		return apierror.FromErr(err)
+	}
+
+	if err := db.WithContext(ctx).Model(&project).
+		Update("preview_token", nil).Error; err != nil {
+		return apierror.FromErr(err)
+	}
+
+	return nil
+}
+
+func (s *service) GetFeaturedProjects(ctx context.Context) (*[]ProjectRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectList []Projects
+	if err := db.WithContext(ctx).
+		Scopes(s.dbSelector.VisibleScope(ctx)).
+		Where("featured").
+		Preload("Tags", orderedTags).
+		Preload("Skills", skill.OrderedSkills).
+		Order("position ASC, created_at DESC").
+		Find(&projectList).Error; err != nil {
+		return nil, err
+	}
+
+	if err := loadProjectImages(db.WithContext(ctx), projectList, false); err != nil {
+		return nil, err
+	}
+
+	res := make([]ProjectRes, len(projectList))
+	for i, p := range projectList {
+		res[i] = *toProjectRes(p)
+	}
+
+	return &res, nil
+}
+
+// ReorderProjects sets the manual order of the portfolio, every project has to be listed once.
+func (s *service) ReorderProjects(ctx context.Context, input ReorderProjectsReq) error {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Model(&Projects{}).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+
+		existing := make(map[uuid.UUID]bool, len(ids))
+		for _, id := range ids {
+			existing[id] = true
+		}
+
+		if len(input.ProjectIDs) != len(existing) {
+			return apierror.InvalidProjectOrder()
+		}
+		for _, id := range input.ProjectIDs {
+			if !existing[id] {
+				return apierror.InvalidProjectOrder()
+			}
+			delete(existing, id)
+		}
+
+		for position, id := range input.ProjectIDs {
+			if err := tx.Model(&Projects{}).
+				Where("id = ?", id).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return apierror.FromErr(err)
+	}
+
+	return nil
+}
+
+func (s *service) SetProjectFeatured(ctx context.Context, projectId string, input SetProjectFeaturedReq) (*ProjectRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := findProjectWithImages(db.WithContext(ctx), projectId)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	project.Featured = *input.Featured
+
+	if err := db.WithContext(ctx).Model(&project).
+		Select("featured").
+		Updates(&project).Error; err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return toProjectRes(project), nil
+}
+
+func findProjectWithImages(tx *gorm.DB, projectId string) (Projects, error) {
+	var project Projects
+
+	id, err := uuid.Parse(projectId)
+	if err != nil {
+		return project, apierror.InvalidProjectId()
+	}
+
+	if err := tx.Preload("Images", orderedImages).
+		Preload("Tags", orderedTags).
+		Preload("Skills", skill.OrderedSkills).
+		First(&project, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return project, apierror.ProjectNotFound(projectId)
+		}
+		return project, err
+	}
+
+	return project, nil
+}
+
+func findProjectImage(tx *gorm.DB, projectId string, imageId string) (ProjectImages, error) {
+	var image ProjectImages
+
+	pid, err := uuid.Parse(projectId)
+	if err != nil {
+		return image, apierror.InvalidProjectId()
+	}
+
+	iid, err := uuid.Parse(imageId)
+	if err != nil {
+		return image, apierror.InvalidProjectImageId()
+	}
+
+	if err := tx.First(&image, "id = ? AND project_id = ?", iid, pid).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return image, apierror.ProjectImageNotFound()
+		}
+		return image, err
+	}
+
+	return image, nil
 }
@@ -0,0 +1,374 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+func newMockService(t *testing.T) (*service, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock := testutil.NewMockDB(t)
+	ownerDB, visitorsDB := &database.OwnerDB{DB: db}, &database.VisitorsDB{DB: db}
+	return &service{
+		dbSelector: dbselector.NewDBService(ownerDB, visitorsDB),
+		OwnerDB:    ownerDB,
+		VisitorsDB: visitorsDB,
+	}, mock
+}
+
+// uploadedFiles lists what is left in the project upload directory.
+func uploadedFiles(t *testing.T) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(projectUploadDir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func expectNewProject(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "projects" WHERE slug = $1 AND id <> $2`)).
+		WithArgs("my-project", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "project_slug_aliases"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "projects" SET "position"=position + 1`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "projects"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+}
+
+func TestCreateProject(t *testing.T) {
+	t.Chdir(t.TempDir())
+	s, mock := newMockService(t)
+
+	expectNewProject(mock)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "project_images"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	res, err := s.CreateProject(context.Background(), CreateProjectReq{
+		Name:   "My Project",
+		Images: []*multipart.FileHeader{testutil.FormFile(t, "a.PNG", []byte("a")), testutil.FormFile(t, "b.jpg", []byte("b"))},
+	})
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Slug != "my-project" || res.Status != constants.STATUS_PUBLISHED {
+		t.Errorf("slug = %s, status = %s", res.Slug, res.Status)
+	}
+	if len(res.Images) != 2 || res.Images[0].Position != 0 || res.Images[1].Position != 1 {
+		t.Fatalf("images = %+v, want two in upload order", res.Images)
+	}
+	if !strings.HasSuffix(res.Images[0].ImgUrl, "_0.png") || !strings.HasSuffix(res.Images[1].ImgUrl, "_1.jpg") {
+		t.Fatalf("images = %+v, want two in upload order", res.Images)
+	}
+	for _, img := range res.Images {
+		if _, err := os.Stat(img.ImgUrl[1:]); err != nil {
+			t.Errorf("image %s was not written: %v", img.ImgUrl, err)
+		}
+	}
+}
+
+// files written before the transaction failed must not be left behind
+func TestCreateProjectRemovesFilesOnError(t *testing.T) {
+	t.Chdir(t.TempDir())
+	s, mock := newMockService(t)
+
+	expectNewProject(mock)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "project_images"`)).
+		WillReturnError(errors.New("connection lost"))
+	mock.ExpectRollback()
+
+	_, err := s.CreateProject(context.Background(), CreateProjectReq{
+		Name:   "My Project",
+		Images: []*multipart.FileHeader{testutil.FormFile(t, "a.png", []byte("a")), testutil.FormFile(t, "b.png", []byte("b"))},
+	})
+	if err == nil {
+		t.Fatal("CreateProject() succeeded")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if files := uploadedFiles(t); len(files) != 0 {
+		t.Errorf("files left behind: %v", files)
+	}
+}
+
+// expectProjectWithImages answers the lookup of findProjectWithImages. The preloads run in
+// no fixed order, so tests using it match expectations out of order.
+func expectProjectWithImages(mock sqlmock.Sqlmock, projectID uuid.UUID, imageIDs ...uuid.UUID) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "projects" WHERE id = $1`)).
+		WithArgs(projectID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "slug"}).AddRow(projectID, "My Project", "my-project"))
+
+	images := sqlmock.NewRows([]string{"id", "project_id", "img_url", "position"})
+	for i, id := range imageIDs {
+		images.AddRow(id, projectID, "/uploads/projects/"+id.String()+".png", i)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "project_images" WHERE "project_images"."project_id" = $1`)).
+		WithArgs(projectID).
+		WillReturnRows(images)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "project_tags"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "tag_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "project_skills"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "skill_id"}))
+}
+
+func TestAddProjectImagesAppends(t *testing.T) {
+	t.Chdir(t.TempDir())
+	s, mock := newMockService(t)
+	mock.MatchExpectationsInOrder(false)
+	projectID, first, second := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectProjectWithImages(mock, projectID, first, second)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "project_images"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	res, err := s.AddProjectImages(context.Background(), projectID.String(), AddProjectImagesReq{
+		Images: []*multipart.FileHeader{testutil.FormFile(t, "c.png", []byte("c"))},
+	})
+	if err != nil {
+		t.Fatalf("AddProjectImages() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Images) != 3 || res.Images[2].Position != 2 {
+		t.Errorf("images = %+v, want the new one last", res.Images)
+	}
+	if files := uploadedFiles(t); len(files) != 1 {
+		t.Errorf("uploaded files = %v, want one", files)
+	}
+}
+
+func TestReorderProjectImages(t *testing.T) {
+	s, mock := newMockService(t)
+	mock.MatchExpectationsInOrder(false)
+	projectID, first, second := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectProjectWithImages(mock, projectID, first, second)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "project_images" SET "position"=$1,"updated_at"=$2 WHERE id = $3`)).
+		WithArgs(0, sqlmock.AnyArg(), second).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "project_images" SET "position"=$1,"updated_at"=$2 WHERE id = $3`)).
+		WithArgs(1, sqlmock.AnyArg(), first).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectProjectWithImages(mock, projectID, second, first)
+	mock.ExpectCommit()
+
+	res, err := s.ReorderProjectImages(context.Background(), projectID.String(), ReorderProjectImagesReq{ImageIDs: []uuid.UUID{second, first}})
+	if err != nil {
+		t.Fatalf("ReorderProjectImages() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Images) != 2 || res.Images[0].ID != second || res.Images[1].ID != first {
+		t.Errorf("images = %+v, want them in the new order", res.Images)
+	}
+}
+
+// the new order has to list every image of the project exactly once
+func TestReorderProjectImagesRefused(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name string
+		ids  []uuid.UUID
+	}{
+		{name: "missing image", ids: []uuid.UUID{first}},
+		{name: "duplicate", ids: []uuid.UUID{first, first}},
+		{name: "image of another project", ids: []uuid.UUID{first, uuid.New()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newMockService(t)
+			mock.MatchExpectationsInOrder(false)
+			projectID := uuid.New()
+
+			mock.ExpectBegin()
+			expectProjectWithImages(mock, projectID, first, second)
+			mock.ExpectRollback()
+
+			_, err := s.ReorderProjectImages(context.Background(), projectID.String(), ReorderProjectImagesReq{ImageIDs: tt.ids})
+			if got := testutil.StatusOf(err); got != http.StatusBadRequest {
+				t.Fatalf("ReorderProjectImages() status = %d (%v), want 400", got, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// the file is only removed once the row is gone
+func TestDeleteProjectImage(t *testing.T) {
+	s, mock := newMockService(t)
+	projectID, imageID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "project_images" WHERE (id = $1 AND project_id = $2) AND "project_images"."deleted_at" IS NULL`)).
+		WithArgs(imageID, projectID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "is_cover"}).AddRow(imageID, projectID, true))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "project_images" SET "deleted_at"=$1,"is_cover"=$2,"updated_at"=$3 WHERE`)).
+		WithArgs(sqlmock.AnyArg(), false, sqlmock.AnyArg(), imageID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := s.DeleteProjectImage(context.Background(), projectID.String(), imageID.String()); err != nil {
+		t.Fatalf("DeleteProjectImage() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteProjectImageNotFound(t *testing.T) {
+	s, mock := newMockService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "project_images"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	err := s.DeleteProjectImage(context.Background(), uuid.NewString(), uuid.NewString())
+	if got := testutil.StatusOf(err); got != http.StatusNotFound {
+		t.Fatalf("DeleteProjectImage() status = %d (%v), want 404", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func expectProjectIds(mock sqlmock.Sqlmock, ids ...uuid.UUID) {
+	rows := sqlmock.NewRows([]string{"id"})
+	for _, id := range ids {
+		rows.AddRow(id)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "projects" WHERE "projects"."deleted_at" IS NULL`)).
+		WillReturnRows(rows)
+}
+
+func TestReorderProjects(t *testing.T) {
+	s, mock := newMockService(t)
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectProjectIds(mock, first, second, third)
+	for position, id := range []uuid.UUID{third, first, second} {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "projects" SET "position"=$1,"updated_at"=$2 WHERE id = $3`)).
+			WithArgs(position, sqlmock.AnyArg(), id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	if err := s.ReorderProjects(context.Background(), ReorderProjectsReq{ProjectIDs: []uuid.UUID{third, first, second}}); err != nil {
+		t.Fatalf("ReorderProjects() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// every project has to be listed exactly once, otherwise nothing is moved
+func TestReorderProjectsRefused(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name string
+		ids  []uuid.UUID
+	}{
+		{name: "missing project", ids: []uuid.UUID{first}},
+		{name: "duplicate", ids: []uuid.UUID{first, first}},
+		{name: "unknown project", ids: []uuid.UUID{first, uuid.New()}},
+		{name: "extra project", ids: []uuid.UUID{first, second, uuid.New()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newMockService(t)
+			mock.ExpectBegin()
+			expectProjectIds(mock, first, second)
+			mock.ExpectRollback()
+
+			err := s.ReorderProjects(context.Background(), ReorderProjectsReq{ProjectIDs: tt.ids})
+			if got := testutil.StatusOf(err); got != http.StatusBadRequest {
+				t.Fatalf("ReorderProjects() status = %d (%v), want 400", got, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestGetFeaturedProjects(t *testing.T) {
+	s, mock := newMockService(t)
+	projectID := uuid.New()
+
+	// gorm runs the preloads in no fixed order
+	mock.MatchExpectationsInOrder(false)
+
+	// visitors only get published featured projects, in the manual order
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "projects" WHERE featured AND "projects"."status" = $1 AND ("projects"."publish_at" IS NULL OR "projects"."publish_at" <= NOW()) AND "projects"."deleted_at" IS NULL ORDER BY position ASC, created_at DESC`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "featured"}).AddRow(projectID, "Portfolio", true))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "project_tags"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "tag_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "project_skills"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "skill_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "project_images" WHERE project_id IN ($1)`)).
+		WithArgs(projectID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "img_url"}))
+
+	res, err := s.GetFeaturedProjects(context.Background())
+	if err != nil {
+		t.Fatalf("GetFeaturedProjects() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if len(*res) != 1 || !(*res)[0].Featured {
+		t.Errorf("got %+v, want the featured project", *res)
+	}
+}
+
+func TestSetProjectFeaturedInvalidId(t *testing.T) {
+	s, mock := newMockService(t)
+	featured := true
+
+	_, err := s.SetProjectFeatured(context.Background(), "nope", SetProjectFeaturedReq{Featured: &featured})
+	if got := testutil.StatusOf(err); got != http.StatusBadRequest {
+		t.Fatalf("SetProjectFeatured() status = %d (%v), want 400", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
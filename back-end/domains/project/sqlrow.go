@@ -4,16 +4,29 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/domains/skill"
+	"github.com/devanadindra/portfolio/back-end/domains/tag"
 )
 
 type Projects struct {
-	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	Name        string
-	Description string
-	ProjectUrl  string
-	Images      []ProjectImages `gorm:"foreignKey:ProjectID;references:ID"`
-	CreatedAt   time.Time       `gorm:"autoCreateTime"`
-	UpdatedAt   time.Time       `gorm:"autoUpdateTime"`
+	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Name         string
+	Slug         string `gorm:"unique"`
+	Description  string
+	ProjectUrl   string
+	Status       string
+	PublishAt    *time.Time
+	PreviewToken *string
+	Position     int
+	Featured     bool
+	Images       []ProjectImages `gorm:"foreignKey:ProjectID;references:ID"`
+	Tags         []tag.Tag       `gorm:"many2many:project_tags;joinForeignKey:ProjectID;joinReferences:TagID"`
+	Skills       []skill.Skill   `gorm:"many2many:project_skills;joinForeignKey:ProjectID;joinReferences:SkillID"`
+	CreatedAt    time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time       `gorm:"autoUpdateTime"`
+	DeletedAt    gorm.DeletedAt
 }
 
 func (Projects) TableName() string {
@@ -24,10 +37,32 @@ type ProjectImages struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	ProjectID uuid.UUID `gorm:"type:uuid;not null"`
 	ImgUrl    string
+	Position  int
+	IsCover   bool
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt
 }
 
 func (ProjectImages) TableName() string {
 	return "project_images"
 }
+
+type ProjectSlugAlias struct {
+	Slug      string    `gorm:"primaryKey"`
+	ProjectID uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (ProjectSlugAlias) TableName() string {
+	return "project_slug_aliases"
+}
+
+type ProjectTags struct {
+	ProjectID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	TagID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (ProjectTags) TableName() string {
+	return "project_tags"
+}
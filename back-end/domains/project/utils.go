@@ -0,0 +1,328 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/domains/skill"
+	"github.com/devanadindra/portfolio/back-end/domains/tag"
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
+	fileutils "github.com/devanadindra/portfolio/back-end/utils/file"
+	"github.com/devanadindra/portfolio/back-end/utils/logger"
+)
+
+const projectUploadDir = "uploads/projects"
+
+// reservedSlugs are static paths of the project routes that would shadow a project slug.
+var reservedSlugs = map[string]bool{
+	"all":      true,
+	"featured": true,
+}
+
+// saveProjectImages writes the uploaded files to disk and returns the rows to insert.
+// urls always contains every file written so far, even when an error is returned,
+// so the caller can clean them up.
+func saveProjectImages(ctx context.Context, projectID uuid.UUID, startPosition int, files []*multipart.FileHeader) (images []ProjectImages, urls []string, err error) {
+	if len(files) == 0 {
+		return nil, nil, nil
+	}
+
+	if err := os.MkdirAll(projectUploadDir, os.ModePerm); err != nil {
+		return nil, nil, err
+	}
+
+	for i, file := range files {
+		filename, err := fileutils.GenerateMediaName(projectID.String())
+		if err != nil {
+			return nil, urls, err
+		}
+
+		filename = fmt.Sprintf("%s_%d%s", filename, i, strings.ToLower(filepath.Ext(file.Filename)))
+		imgUrl := "/" + projectUploadDir + "/" + filename
+		urls = append(urls, imgUrl)
+		if err := fileutils.SaveMedia(ctx, file, filepath.Join(projectUploadDir, filename)); err != nil {
+			return nil, urls, err
+		}
+
+		images = append(images, ProjectImages{
+			ID:        uuid.New(),
+			ProjectID: projectID,
+			ImgUrl:    imgUrl,
+			Position:  startPosition + i,
+		})
+	}
+
+	return images, urls, nil
+}
+
+// removeProjectFiles deletes files from disk, logging failures instead of returning them
+// because it is only used for cleanup after the database work has been decided.
+func removeProjectFiles(ctx context.Context, urls []string) {
+	for _, url := range urls {
+		if err := fileutils.RemoveMedia(url); err != nil {
+			logger.Error(ctx, "%v", err)
+		}
+	}
+}
+
+func toProjectRes(p Projects) *ProjectRes {
+	images := make([]ProjectImagesRes, len(p.Images))
+	for i, img := range p.Images {
+		images[i] = ProjectImagesRes{
+			ID:        img.ID,
+			ProjectID: img.ProjectID,
+			ImgUrl:    img.ImgUrl,
+			Position:  img.Position,
+			IsCover:   img.IsCover,
+		}
+	}
+
+	return &ProjectRes{
+		ID:          p.ID,
+		Name:        p.Name,
+		Slug:        p.Slug,
+		Description: p.Description,
+		ProjectUrl:  p.ProjectUrl,
+		Status:      p.Status,
+		PublishAt:   p.PublishAt,
+		Position:    p.Position,
+		Featured:    p.Featured,
+		Images:      images,
+		Tags:        toTagsRes(p.Tags),
+		Skills:      skill.ToSkillSummaries(p.Skills),
+	}
+}
+
+func toTagsRes(tags []tag.Tag) []tag.TagRes {
+	res := make([]tag.TagRes, len(tags))
+	for i, t := range tags {
+		res[i] = tag.ToTagRes(t)
+	}
+	return res
+}
+
+// orderedImages is used with Preload("Images", ...) so galleries come back in display order.
+func orderedImages(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, created_at ASC")
+}
+
+// loadProjectImages fills the Images of an already fetched page of projects with one query.
+// Unless all is set only the cover, or the first image when there is no cover, is loaded.
+func loadProjectImages(db *gorm.DB, projects []Projects, all bool) error {
+	if len(projects) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(projects))
+	for i, p := range projects {
+		ids[i] = p.ID
+	}
+
+	var images []ProjectImages
+	query := db.Where("project_id IN ?", ids)
+	if all {
+		query = query.Scopes(orderedImages)
+	} else {
+		query = query.
+			Select("DISTINCT ON (project_id) *").
+			Order("project_id, is_cover DESC, position ASC, created_at ASC")
+	}
+	if err := query.Find(&images).Error; err != nil {
+		return err
+	}
+
+	byProject := make(map[uuid.UUID][]ProjectImages, len(projects))
+	for _, img := range images {
+		byProject[img.ProjectID] = append(byProject[img.ProjectID], img)
+	}
+	for i := range projects {
+		projects[i].Images = byProject[projects[i].ID]
+	}
+
+	return nil
+}
+
+// uniqueSlug returns a slug for name that is not used by another project, either as its
+// current slug or as one of its aliases.
+func uniqueSlug(tx *gorm.DB, name string, projectID uuid.UUID) (string, error) {
+	base := common.Slugify(name)
+	if base == "" {
+		base = "project"
+	}
+	candidate := base
+
+	for i := 2; ; i++ {
+		if reservedSlugs[candidate] {
+			candidate = fmt.Sprintf("%s-%d", base, i)
+			continue
+		}
+
+		var taken int64
+		// trashed projects keep their slug so they can be restored
+		if err := tx.Unscoped().Model(&Projects{}).
+			Where("slug = ? AND id <> ?", candidate, projectID).
+			Count(&taken).Error; err != nil {
+			return "", err
+		}
+
+		if taken == 0 {
+			if err := tx.Model(&ProjectSlugAlias{}).
+				Where("slug = ? AND project_id <> ?", candidate, projectID).
+				Count(&taken).Error; err != nil {
+				return "", err
+			}
+		}
+
+		if taken == 0 {
+			return candidate, nil
+		}
+
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// renameSlug moves project to a slug generated from its current name and keeps the previous
+// slug as an alias so old links still resolve.
+func renameSlug(tx *gorm.DB, project *Projects) error {
+	newSlug, err := uniqueSlug(tx, project.Name, project.ID)
+	if err != nil {
+		return err
+	}
+
+	if newSlug == project.Slug {
+		return nil
+	}
+
+	// the project may be going back to a name it used before
+	if err := tx.Where("slug = ? AND project_id = ?", newSlug, project.ID).
+		Delete(&ProjectSlugAlias{}).Error; err != nil {
+		return err
+	}
+
+	if project.Slug != "" {
+		if err := tx.Create(&ProjectSlugAlias{
+			Slug:      project.Slug,
+			ProjectID: project.ID,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	project.Slug = newSlug
+
+	return nil
+}
+
+func orderedTags(db *gorm.DB) *gorm.DB {
+	return db.Order("name ASC")
+}
+
+// setProjectTags replaces the tags of a project, every id must belong to an existing tag.
+func setProjectTags(tx *gorm.DB, projectID uuid.UUID, tagIds []string) ([]tag.Tag, error) {
+	tagIds = common.UniqueArray(tagIds)
+
+	tags := make([]tag.Tag, 0, len(tagIds))
+	if len(tagIds) > 0 {
+		if err := tx.Where("id IN ?", tagIds).Order("name ASC").Find(&tags).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if len(tags) != len(tagIds) {
+		found := make(map[string]bool, len(tags))
+		for _, t := range tags {
+			found[t.ID.String()] = true
+		}
+		for _, id := range tagIds {
+			if !found[id] {
+				return nil, apierror.TagNotFound(id)
+			}
+		}
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&ProjectTags{}).Error; err != nil {
+		return nil, err
+	}
+
+	if len(tags) == 0 {
+		return tags, nil
+	}
+
+	rows := make([]ProjectTags, len(tags))
+	for i, t := range tags {
+		rows[i] = ProjectTags{ProjectID: projectID, TagID: t.ID}
+	}
+
+	if err := tx.Create(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// tagFilterScope keeps projects tagged with the given tag slugs. In "and" mode a project
+// must carry every tag, otherwise any of them is enough.
+func tagFilterScope(slugs []string, mode string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(slugs) == 0 {
+			return db
+		}
+
+		sub := db.Session(&gorm.Session{NewDB: true}).
+			Table("project_tags AS pt").
+			Select("pt.project_id").
+			Joins("JOIN tags AS t ON t.id = pt.tag_id").
+			Where("t.slug IN ?", slugs)
+
+		if mode == TAG_MODE_AND {
+			sub = sub.Group("pt.project_id").
+				Having("COUNT(DISTINCT t.id) = ?", len(slugs))
+		}
+
+		return db.Where("projects.id IN (?)", sub)
+	}
+}
+
+// skillFilterScope keeps projects linked to the skill, either directly or through one of
+// their tags.
+func skillFilterScope(skillId string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if skillId == "" {
+			return db
+		}
+
+		byTag := db.Session(&gorm.Session{NewDB: true}).
+			Table("project_tags AS pt").
+			Select("pt.project_id").
+			Joins("JOIN tags AS t ON t.id = pt.tag_id").
+			Where("t.skill_id = ?", skillId)
+
+		direct := db.Session(&gorm.Session{NewDB: true}).
+			Table("project_skills AS ps").
+			Select("ps.project_id").
+			Where("ps.skill_id = ?", skillId)
+
+		return db.Where("projects.id IN (?) OR projects.id IN (?)", byTag, direct)
+	}
+}
+
+// splitQueryValues accepts both repeated (?tag=a&tag=b) and comma separated (?tag=a,b) values.
+func splitQueryValues(values []string) []string {
+	res := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				res = append(res, strings.ToLower(v))
+			}
+		}
+	}
+	return common.UniqueArray(res)
+}
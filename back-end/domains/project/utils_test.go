@@ -0,0 +1,244 @@
+package project
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+func expectSlugTaken(mock sqlmock.Sqlmock, slug string, projectID uuid.UUID, byProject int, byAlias int) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "projects" WHERE slug = $1 AND id <> $2`)).
+		WithArgs(slug, projectID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(byProject))
+	if byProject > 0 {
+		return
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "project_slug_aliases" WHERE slug = $1 AND project_id <> $2`)).
+		WithArgs(slug, projectID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(byAlias))
+}
+
+func TestUniqueSlug(t *testing.T) {
+	projectID := uuid.New()
+
+	tests := []struct {
+		name   string
+		input  string
+		expect func(mock sqlmock.Sqlmock)
+		want   string
+	}{
+		{
+			name:  "free",
+			input: "My Project",
+			expect: func(mock sqlmock.Sqlmock) {
+				expectSlugTaken(mock, "my-project", projectID, 0, 0)
+			},
+			want: "my-project",
+		},
+		{
+			name:  "taken by a project and an alias",
+			input: "My Project",
+			expect: func(mock sqlmock.Sqlmock) {
+				expectSlugTaken(mock, "my-project", projectID, 1, 0)
+				expectSlugTaken(mock, "my-project-2", projectID, 0, 1)
+				expectSlugTaken(mock, "my-project-3", projectID, 0, 0)
+			},
+			want: "my-project-3",
+		},
+		{
+			name:  "reserved",
+			input: "Featured",
+			expect: func(mock sqlmock.Sqlmock) {
+				expectSlugTaken(mock, "featured-2", projectID, 0, 0)
+			},
+			want: "featured-2",
+		},
+		{
+			name:  "nothing to slugify",
+			input: "!!!",
+			expect: func(mock sqlmock.Sqlmock) {
+				expectSlugTaken(mock, "project", projectID, 0, 0)
+			},
+			want: "project",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := testutil.NewMockDB(t)
+			tt.expect(mock)
+
+			got, err := uniqueSlug(db, tt.input, projectID)
+			if err != nil {
+				t.Fatalf("uniqueSlug() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("uniqueSlug() = %q, want %q", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestRenameSlugKeepsAlias(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	project := &Projects{ID: uuid.New(), Name: "New Name", Slug: "old-name"}
+
+	expectSlugTaken(mock, "new-name", project.ID, 0, 0)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "project_slug_aliases" WHERE slug = $1 AND project_id = $2`)).
+		WithArgs("new-name", project.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "project_slug_aliases"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := renameSlug(db, project); err != nil {
+		t.Fatalf("renameSlug() error = %v", err)
+	}
+	if project.Slug != "new-name" {
+		t.Errorf("slug = %q, want new-name", project.Slug)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSplitQueryValues(t *testing.T) {
+	got := splitQueryValues([]string{"Go, react", "go", " ,vue ", ""})
+	if !slices.Equal(got, []string{"go", "react", "vue"}) {
+		t.Errorf("splitQueryValues() = %v", got)
+	}
+}
+
+func TestTagFilterScope(t *testing.T) {
+	db, _ := testutil.NewMockDB(t)
+
+	tests := []struct {
+		name  string
+		slugs []string
+		mode  string
+		want  string
+	}{
+		{name: "no tags", want: `SELECT * FROM "projects"`},
+		{
+			name:  "any tag",
+			slugs: []string{"go", "react"},
+			mode:  TAG_MODE_OR,
+			want:  `SELECT * FROM "projects" WHERE projects.id IN (SELECT pt.project_id FROM project_tags AS pt JOIN tags AS t ON t.id = pt.tag_id WHERE t.slug IN ('go','react'))`,
+		},
+		{
+			name:  "every tag",
+			slugs: []string{"go", "react"},
+			mode:  TAG_MODE_AND,
+			want:  `SELECT * FROM "projects" WHERE projects.id IN (SELECT pt.project_id FROM project_tags AS pt JOIN tags AS t ON t.id = pt.tag_id WHERE t.slug IN ('go','react') GROUP BY "pt"."project_id" HAVING COUNT(DISTINCT t.id) = 2)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return tx.Unscoped().Scopes(tagFilterScope(tt.slugs, tt.mode)).Find(&[]Projects{})
+			})
+			if got != tt.want {
+				t.Errorf("sql =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkillFilterScope(t *testing.T) {
+	db, _ := testutil.NewMockDB(t)
+	skillId := uuid.NewString()
+
+	got := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Unscoped().Scopes(skillFilterScope(skillId)).Find(&[]Projects{})
+	})
+	want := `SELECT * FROM "projects" WHERE projects.id IN (SELECT pt.project_id FROM project_tags AS pt JOIN tags AS t ON t.id = pt.tag_id WHERE t.skill_id = '` + skillId + `') OR projects.id IN (SELECT ps.project_id FROM project_skills AS ps WHERE ps.skill_id = '` + skillId + `')`
+	if got != want {
+		t.Errorf("sql =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSetProjectTagsUnknownTag(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	known, unknown := uuid.New(), uuid.NewString()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE id IN ($1,$2)`)).
+		WithArgs(known.String(), unknown).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(known, "Go"))
+
+	_, err := setProjectTags(db, uuid.New(), []string{known.String(), unknown, known.String()})
+	if got := testutil.StatusOf(err); got != http.StatusNotFound {
+		t.Fatalf("setProjectTags() status = %d (%v), want 404", got, err)
+	}
+	// the current tags are left alone
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadProjectImages(t *testing.T) {
+	first, second, empty := uuid.New(), uuid.New(), uuid.New()
+	projects := []Projects{{ID: first}, {ID: second}, {ID: empty}}
+
+	tests := []struct {
+		name string
+		all  bool
+		sql  string
+	}{
+		{name: "covers", sql: `SELECT DISTINCT ON (project_id) * FROM "project_images" WHERE project_id IN ($1,$2,$3) AND "project_images"."deleted_at" IS NULL ORDER BY project_id, is_cover DESC, position ASC, created_at ASC`},
+		{name: "all", all: true, sql: `SELECT * FROM "project_images" WHERE project_id IN ($1,$2,$3) AND "project_images"."deleted_at" IS NULL ORDER BY position ASC, created_at ASC`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := testutil.NewMockDB(t)
+			// one query for the whole page
+			mock.ExpectQuery(regexp.QuoteMeta(tt.sql)+`$`).
+				WithArgs(first, second, empty).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "img_url"}).
+					AddRow(uuid.New(), second, "/b.png").
+					AddRow(uuid.New(), first, "/a.png"))
+
+			page := slices.Clone(projects)
+			if err := loadProjectImages(db, page, tt.all); err != nil {
+				t.Fatalf("loadProjectImages() error = %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(page[0].Images) != 1 || page[0].Images[0].ImgUrl != "/a.png" {
+				t.Errorf("first project images = %+v", page[0].Images)
+			}
+			if len(page[1].Images) != 1 || page[1].Images[0].ImgUrl != "/b.png" {
+				t.Errorf("second project images = %+v", page[1].Images)
+			}
+			if len(page[2].Images) != 0 {
+				t.Errorf("project without images got %+v", page[2].Images)
+			}
+		})
+	}
+}
+
+func TestLoadProjectImagesEmptyPage(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	if err := loadProjectImages(db, nil, false); err != nil {
+		t.Fatalf("loadProjectImages() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
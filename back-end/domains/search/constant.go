@@ -0,0 +1,15 @@
+package search
+
+const (
+	TYPE_PROJECT     = "project"
+	TYPE_CERTIFICATE = "certificate"
+	TYPE_SKILL       = "skill"
+)
+
+// ts_headline marks matches with control characters instead of <mark>, the body is user
+// content so the snippet is html escaped first and the markers turned into <mark> after
+const (
+	headlineStart   = "\x01"
+	headlineStop    = "\x02"
+	headlineOptions = "StartSel=" + headlineStart + ", StopSel=" + headlineStop + ", MaxWords=35, MinWords=15, MaxFragments=2"
+)
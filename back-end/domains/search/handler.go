@@ -0,0 +1,63 @@
+package search
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
+	"github.com/devanadindra/portfolio/back-end/utils/respond"
+)
+
+type Handler interface {
+	Search(ctx *gin.Context)
+}
+
+type handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service, validate *validator.Validate) Handler {
+	return &handler{
+		service:  service,
+		validate: validate,
+	}
+}
+
+func (h *handler) Search(ctx *gin.Context) {
+	var input SearchReq
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	// results are always ranked, only page and limit are taken from the metadata
+	filter, err := common.GetMetaData(ctx, h.validate, "rank")
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+	input.Page = filter.Page
+	input.Limit = filter.Limit
+
+	res, total, err := h.service.Search(ctx, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{
+		"data":  res,
+		"total": total,
+		"page":  filter.Page,
+		"limit": filter.Limit,
+	})
+}
@@ -0,0 +1,8 @@
+package search
+
+type SearchReq struct {
+	Query string `form:"q" validate:"required,max=200"`
+	Type  string `form:"type" validate:"omitempty,oneof=project certificate skill"`
+	Page  int64
+	Limit int64
+}
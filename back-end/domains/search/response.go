@@ -0,0 +1,11 @@
+package search
+
+import "github.com/google/uuid"
+
+type SearchRes struct {
+	Type    string    `json:"type"`
+	ID      uuid.UUID `json:"id"`
+	Title   string    `json:"title"`
+	Snippet string    `json:"snippet"`
+	Rank    float64   `json:"rank"`
+}
@@ -0,0 +1,86 @@
+package search
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+)
+
+type Service interface {
+	Search(ctx context.Context, input SearchReq) (*[]SearchRes, int64, error)
+}
+
+type service struct {
+	authConfig config.Auth
+	dbSelector *dbselector.DBService
+	VisitorsDB *database.VisitorsDB
+	OwnerDB    *database.OwnerDB
+}
+
+func NewService(config *config.Config, dbSelector *dbselector.DBService, VisitorsDB *database.VisitorsDB, OwnerDB *database.OwnerDB) Service {
+	return &service{
+		authConfig: config.Auth,
+		dbSelector: dbSelector,
+		VisitorsDB: VisitorsDB,
+		OwnerDB:    OwnerDB,
+	}
+}
+
+func (s *service) Search(ctx context.Context, input SearchReq) (*[]SearchRes, int64, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	isOwner := s.dbSelector.IsOwner(ctx)
+
+	matches := func() *gorm.DB {
+		query := db.WithContext(ctx).
+			Table("search_documents AS d, websearch_to_tsquery('english', ?) AS q", input.Query).
+			Where("d.search_vector @@ q")
+		if !isOwner {
+			query = query.Where("d.status = ? AND (d.publish_at IS NULL OR d.publish_at <= NOW())", constants.STATUS_PUBLISHED)
+		}
+		if input.Type != "" {
+			query = query.Where("d.type = ?", input.Type)
+		}
+		return query
+	}
+
+	var total int64
+	if err := matches().Count(&total).Error; err != nil {
+		return nil, 0, apierror.FromErr(err)
+	}
+
+	res := make([]SearchRes, 0)
+	if total == 0 {
+		return &res, 0, nil
+	}
+
+	offset := (input.Page - 1) * input.Limit
+
+	err = matches().
+		Select(
+			"d.type, d.id, d.title, ts_headline('english', d.body, q, ?) AS snippet, ts_rank(d.search_vector, q) AS rank",
+			headlineOptions,
+		).
+		Order("rank DESC, d.title ASC").
+		Limit(int(input.Limit)).
+		Offset(int(offset)).
+		Scan(&res).Error
+	if err != nil {
+		return nil, 0, apierror.FromErr(err)
+	}
+
+	for i := range res {
+		res[i].Snippet = highlightSnippet(res[i].Snippet)
+	}
+
+	return &res, total, nil
+}
@@ -0,0 +1,14 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+var markReplacer = strings.NewReplacer(headlineStart, "<mark>", headlineStop, "</mark>")
+
+// highlightSnippet escapes a ts_headline result and wraps the matches in <mark>, the only
+// markup the snippet can contain.
+func highlightSnippet(snippet string) string {
+	return markReplacer.Replace(html.EscapeString(snippet))
+}
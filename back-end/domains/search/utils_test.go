@@ -0,0 +1,25 @@
+package search
+
+import "testing"
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{"plain", "a go project", "a go project"},
+		{"match", "a \x01go\x02 project", "a <mark>go</mark> project"},
+		{"markup escaped", "<script>alert(1)</script> \x01go\x02", "&lt;script&gt;alert(1)&lt;/script&gt; <mark>go</mark>"},
+		{"literal mark escaped", "<mark>x</mark>", "&lt;mark&gt;x&lt;/mark&gt;"},
+		{"attribute", `<img src=x onerror="a">`, "&lt;img src=x onerror=&#34;a&#34;&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightSnippet(tt.snippet); got != tt.want {
+				t.Errorf("highlightSnippet(%q) = %q, want %q", tt.snippet, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package skill
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+)
+
+// GetGroupedSkills returns every skill grouped by category, categories in display order and
+// skills inside them in the requested order. Paging does not apply to the grouped view.
+func (s *service) GetGroupedSkills(ctx context.Context, filter constants.FilterReq) (*[]SkillGroupRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []SkillCategory
+	if err := db.WithContext(ctx).Order("position ASC, name ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	if filter.OrderBy == "experience" {
+		filter.OrderBy = experienceOrder
+	}
+
+	var skills []Skill
+	if err := db.WithContext(ctx).
+		Scopes(common.FilterScope(filter, "name")).
+		Order(fmt.Sprintf("%s %s", filter.OrderBy, filter.SortOrder)).
+		Order("name ASC").
+		Find(&skills).Error; err != nil {
+		return nil, err
+	}
+
+	res := GroupSkills(categories, skills)
+	return &res, nil
+}
+
+func (s *service) GetSkillCategories(ctx context.Context) (*[]SkillCategoryRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []SkillCategory
+	if err := db.WithContext(ctx).Order("position ASC, name ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]SkillCategoryRes, len(categories))
+	for i, c := range categories {
+		res[i] = ToSkillCategoryRes(c)
+	}
+
+	return &res, nil
+}
+
+// CreateSkillCategory adds the category after the existing ones.
+func (s *service) CreateSkillCategory(ctx context.Context, input CreateSkillCategoryReq) (*SkillCategoryRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	category := SkillCategory{
+		ID:   uuid.New(),
+		Name: input.Name,
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureCategoryNameFree(tx, category.Name, category.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&SkillCategory{}).
+			Select("COALESCE(MAX(position) + 1, 0)").
+			Scan(&category.Position).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&category).Error
+	})
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	res := ToSkillCategoryRes(category)
+	return &res, nil
+}
+
+func (s *service) UpdateSkillCategory(ctx context.Context, categoryId string, input UpdateSkillCategoryReq) (*SkillCategoryRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var category SkillCategory
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		category, err = findCategory(tx, categoryId)
+		if err != nil {
+			return err
+		}
+
+		if input.Name != nil {
+			if err := ensureCategoryNameFree(tx, *input.Name, category.ID); err != nil {
+				return err
+			}
+			category.Name = *input.Name
+		}
+
+		return tx.Save(&category).Error
+	})
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	res := ToSkillCategoryRes(category)
+	return &res, nil
+}
+
+func (s *service) ReorderSkillCategories(ctx context.Context, input ReorderSkillCategoriesReq) error {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Model(&SkillCategory{}).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+
+		existing := make(map[uuid.UUID]bool, len(ids))
+		for _, id := range ids {
+			existing[id] = true
+		}
+
+		if len(input.CategoryIDs) != len(existing) {
+			return apierror.InvalidSkillCategoryOrder()
+		}
+		for _, id := range input.CategoryIDs {
+			if !existing[id] {
+				return apierror.InvalidSkillCategoryOrder()
+			}
+			delete(existing, id)
+		}
+
+		for position, id := range input.CategoryIDs {
+			if err := tx.Model(&SkillCategory{}).
+				Where("id = ?", id).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return apierror.FromErr(err)
+	}
+
+	return nil
+}
+
+// DeleteSkillCategory only removes empty categories, skills in the trash still count because
+// restoring them needs their category.
+func (s *service) DeleteSkillCategory(ctx context.Context, categoryId string) error {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		category, err := findCategory(tx, categoryId)
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Unscoped().Model(&Skill{}).
+			Where("category_id = ?", category.ID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return apierror.SkillCategoryNotEmpty(count)
+		}
+
+		return tx.Delete(&category).Error
+	})
+	if err != nil {
+		return apierror.FromErr(err)
+	}
+
+	return nil
+}
+
+// MoveSkills puts the given skills into the category and returns the category with all its skills.
+// Trashed skills can be moved as well, they keep a category from being deleted.
+func (s *service) MoveSkills(ctx context.Context, categoryId string, input MoveSkillsReq) (*SkillGroupRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res SkillGroupRes
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		category, err := findCategory(tx, categoryId)
+		if err != nil {
+			return err
+		}
+
+		skillIds := common.UniqueArray(input.SkillIDs)
+
+		var found []uuid.UUID
+		if err := tx.Unscoped().Model(&Skill{}).Where("id IN ?", skillIds).Pluck("id", &found).Error; err != nil {
+			return err
+		}
+		if len(found) != len(skillIds) {
+			existing := make(map[uuid.UUID]bool, len(found))
+			for _, id := range found {
+				existing[id] = true
+			}
+			for _, id := range skillIds {
+				if !existing[id] {
+					return apierror.SkillNotFound(id.String())
+				}
+			}
+		}
+
+		if err := tx.Unscoped().Model(&Skill{}).
+			Where("id IN ?", skillIds).
+			Update("category_id", category.ID).Error; err != nil {
+			return err
+		}
+
+		var skills []Skill
+		if err := tx.Where("category_id = ?", category.ID).
+			Order("name ASC").
+			Find(&skills).Error; err != nil {
+			return err
+		}
+
+		res.SkillCategoryRes = ToSkillCategoryRes(category)
+		res.Skills = make([]SkillRes, len(skills))
+		for i, sk := range skills {
+			res.Skills[i] = ToSkillRes(sk)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,128 @@
+package skill
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+// A trashed skill still keeps its category from being deleted, so it has to be movable.
+func TestMoveSkillsIncludesTrashed(t *testing.T) {
+	s, mock := newMockService(t)
+	categoryID := uuid.New()
+	trashed := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skill_categories" WHERE id = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(categoryID, "Backend"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "skills" WHERE id IN ($1)`) + `$`).
+		WithArgs(trashed).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(trashed))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "skills" SET "category_id"=$1,"updated_at"=$2 WHERE id IN ($3)`)+`$`).
+		WithArgs(categoryID, sqlmock.AnyArg(), trashed).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skills" WHERE category_id = $1 AND "skills"."deleted_at" IS NULL`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	res, err := s.MoveSkills(context.Background(), categoryID.String(), MoveSkillsReq{SkillIDs: []uuid.UUID{trashed}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Skills) != 0 {
+		t.Errorf("skills = %d, want the trashed skill left out of the listing", len(res.Skills))
+	}
+}
+
+// categories keep their display order, empty ones are left out of the grouped view
+func TestGroupSkills(t *testing.T) {
+	backend, empty, tools := uuid.New(), uuid.New(), uuid.New()
+	categories := []SkillCategory{{ID: backend, Name: "Backend"}, {ID: empty, Name: "Empty"}, {ID: tools, Name: "Tools"}}
+	skills := []Skill{
+		{ID: uuid.New(), Name: "Git", CategoryID: tools},
+		{ID: uuid.New(), Name: "Go", CategoryID: backend},
+		{ID: uuid.New(), Name: "SQL", CategoryID: backend},
+	}
+
+	groups := GroupSkills(categories, skills)
+	if len(groups) != 2 || groups[0].Name != "Backend" || groups[1].Name != "Tools" {
+		t.Fatalf("groups = %+v, want Backend then Tools", groups)
+	}
+	if len(groups[0].Skills) != 2 || groups[0].Skills[0].Name != "Go" || groups[0].Skills[1].Name != "SQL" {
+		t.Errorf("backend skills = %+v, want them in the given order", groups[0].Skills)
+	}
+}
+
+func TestCreateSkillCategoryDuplicateName(t *testing.T) {
+	s, mock := newMockService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "skill_categories" WHERE LOWER(name) = LOWER($1) AND id <> $2`)).
+		WithArgs("tools", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	_, err := s.CreateSkillCategory(context.Background(), CreateSkillCategoryReq{Name: "tools"})
+	if got := testutil.StatusOf(err); got != http.StatusConflict {
+		t.Fatalf("CreateSkillCategory() status = %d (%v), want 409", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateSkillCategoryGoesLast(t *testing.T) {
+	s, mock := newMockService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "skill_categories"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(position) + 1, 0) FROM "skill_categories"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "skill_categories"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	res, err := s.CreateSkillCategory(context.Background(), CreateSkillCategoryReq{Name: "Cloud"})
+	if err != nil {
+		t.Fatalf("CreateSkillCategory() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if res.Position != 3 {
+		t.Errorf("position = %d, want 3", res.Position)
+	}
+}
+
+// trashed skills count, restoring them needs their category
+func TestDeleteSkillCategoryNotEmpty(t *testing.T) {
+	s, mock := newMockService(t)
+	categoryID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skill_categories" WHERE id = $1`)).
+		WithArgs(categoryID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(categoryID, "Backend"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "skills" WHERE category_id = $1`) + `$`).
+		WithArgs(categoryID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	err := s.DeleteSkillCategory(context.Background(), categoryID.String())
+	if got := testutil.StatusOf(err); got != http.StatusConflict {
+		t.Fatalf("DeleteSkillCategory() status = %d (%v), want 409", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,13 @@
+package skill
+
+// unit of the experience reported in SkillRes
+const (
+	PERIOD_MONTHS = "MONTHS"
+	PERIOD_YEARS  = "YEARS"
+)
+
+// experienceOrder orders skills by the days between started_at and ended_at, or today.
+const experienceOrder = "(COALESCE(ended_at, CURRENT_DATE) - started_at)"
+
+// value of the "group" query parameter of the skill list
+const GROUP_CATEGORY = "category"
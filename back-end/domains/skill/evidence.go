@@ -0,0 +1,160 @@
+package skill
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+)
+
+// projects and certificates are read through their tables, both domains import this one
+
+// GetSkillById returns the skill with the projects and certificates demonstrating it.
+// Visitors only see published ones.
+func (s *service) GetSkillById(ctx context.Context, skillId string) (*SkillDetailRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sk, err := findSkill(db.WithContext(ctx), skillId)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	res, err := s.toSkillDetailRes(ctx, db.WithContext(ctx), sk)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) AttachProject(ctx context.Context, skillId string, projectId string) (*SkillDetailRes, error) {
+	return s.updateEvidence(ctx, skillId, func(tx *gorm.DB, sk Skill) error {
+		id, err := findEvidence(tx, "projects", projectId, apierror.InvalidProjectId(), apierror.ProjectNotFound(projectId))
+		if err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&ProjectSkill{ProjectID: id, SkillID: sk.ID}).Error
+	})
+}
+
+func (s *service) DetachProject(ctx context.Context, skillId string, projectId string) (*SkillDetailRes, error) {
+	return s.updateEvidence(ctx, skillId, func(tx *gorm.DB, sk Skill) error {
+		id, err := uuid.Parse(projectId)
+		if err != nil {
+			return apierror.InvalidProjectId()
+		}
+
+		return tx.Where("project_id = ? AND skill_id = ?", id, sk.ID).Delete(&ProjectSkill{}).Error
+	})
+}
+
+func (s *service) AttachCertificate(ctx context.Context, skillId string, certifId string) (*SkillDetailRes, error) {
+	return s.updateEvidence(ctx, skillId, func(tx *gorm.DB, sk Skill) error {
+		id, err := findEvidence(tx, "certificate", certifId, apierror.InvalidCertifId(), apierror.CertifNotFound(certifId))
+		if err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&CertificateSkill{CertificateID: id, SkillID: sk.ID}).Error
+	})
+}
+
+func (s *service) DetachCertificate(ctx context.Context, skillId string, certifId string) (*SkillDetailRes, error) {
+	return s.updateEvidence(ctx, skillId, func(tx *gorm.DB, sk Skill) error {
+		id, err := uuid.Parse(certifId)
+		if err != nil {
+			return apierror.InvalidCertifId()
+		}
+
+		return tx.Where("certificate_id = ? AND skill_id = ?", id, sk.ID).Delete(&CertificateSkill{}).Error
+	})
+}
+
+// updateEvidence runs change for the skill in a transaction and returns the skill with its
+// evidence afterwards. Attaching an existing link or detaching a missing one is not an error.
+func (s *service) updateEvidence(ctx context.Context, skillId string, change func(tx *gorm.DB, sk Skill) error) (*SkillDetailRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *SkillDetailRes
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		sk, err := findSkill(tx, skillId)
+		if err != nil {
+			return err
+		}
+
+		if err := change(tx, sk); err != nil {
+			return err
+		}
+
+		res, err = s.toSkillDetailRes(ctx, tx, sk)
+		return err
+	})
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return res, nil
+}
+
+func (s *service) toSkillDetailRes(ctx context.Context, tx *gorm.DB, sk Skill) (*SkillDetailRes, error) {
+	res := SkillDetailRes{
+		SkillRes:     ToSkillRes(sk),
+		Projects:     []SkillProjectRes{},
+		Certificates: []SkillCertificateRes{},
+	}
+
+	if err := tx.Table("projects").
+		Select("projects.id", "projects.name", "projects.slug").
+		Joins("JOIN project_skills ON project_skills.project_id = projects.id").
+		Where("project_skills.skill_id = ? AND projects.deleted_at IS NULL", sk.ID).
+		Scopes(s.dbSelector.VisibleScope(ctx)).
+		Order("projects.position ASC").
+		Scan(&res.Projects).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Table("certificate").
+		Select("certificate.id", "certificate.name", "certificate.issuer", "certificate.img_url").
+		Joins("JOIN certificate_skills ON certificate_skills.certificate_id = certificate.id").
+		Where("certificate_skills.skill_id = ? AND certificate.deleted_at IS NULL", sk.ID).
+		Scopes(s.dbSelector.VisibleScope(ctx)).
+		Order("certificate.issued_at DESC NULLS LAST, certificate.name ASC").
+		Scan(&res.Certificates).Error; err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// findEvidence checks that a project or certificate exists and is not in the trash.
+func findEvidence(tx *gorm.DB, table string, rawId string, invalid error, notFound error) (uuid.UUID, error) {
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		return uuid.Nil, invalid
+	}
+
+	var count int64
+	if err := tx.Table(table).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Count(&count).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	if count == 0 {
+		return uuid.Nil, notFound
+	}
+
+	return id, nil
+}
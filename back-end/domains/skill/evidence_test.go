@@ -0,0 +1,163 @@
+package skill
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+	"github.com/google/uuid"
+)
+
+func expectSkill(mock sqlmock.Sqlmock, skillID uuid.UUID) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skills" WHERE id = $1`)).
+		WithArgs(skillID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(skillID, "Go"))
+}
+
+func expectEvidenceCount(mock sqlmock.Sqlmock, table string, id uuid.UUID, count int) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "` + table + `" WHERE id = $1 AND deleted_at IS NULL`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+func TestAttachProject(t *testing.T) {
+	s, mock := newMockService(t)
+	skillID, projectID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectSkill(mock, skillID)
+	expectEvidenceCount(mock, "projects", projectID, 1)
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "project_skills" ("project_id","skill_id","created_at") VALUES ($1,$2,$3) ON CONFLICT DO NOTHING`)).
+		WithArgs(projectID, skillID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// visitors only see published projects and certificates
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT projects.id,projects.name,projects.slug FROM "projects" JOIN project_skills ON project_skills.project_id = projects.id WHERE (project_skills.skill_id = $1 AND projects.deleted_at IS NULL) AND "projects"."status" = $2`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "slug"}).AddRow(projectID, "Portfolio", "portfolio"))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "certificate" JOIN certificate_skills ON certificate_skills.certificate_id = certificate.id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	res, err := s.AttachProject(context.Background(), skillID.String(), projectID.String())
+	if err != nil {
+		t.Fatalf("AttachProject() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Projects) != 1 || res.Projects[0].Slug != "portfolio" {
+		t.Errorf("projects = %+v, want the attached project", res.Projects)
+	}
+	if res.Certificates == nil {
+		t.Error("certificates is nil, want an empty list")
+	}
+}
+
+// a project in the trash cannot be used as evidence
+func TestAttachProjectNotFound(t *testing.T) {
+	s, mock := newMockService(t)
+	skillID, projectID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectSkill(mock, skillID)
+	expectEvidenceCount(mock, "projects", projectID, 0)
+	mock.ExpectRollback()
+
+	_, err := s.AttachProject(context.Background(), skillID.String(), projectID.String())
+	if got := testutil.StatusOf(err); got != http.StatusNotFound {
+		t.Fatalf("AttachProject() status = %d (%v), want 404", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAttachCertificateNotFound(t *testing.T) {
+	s, mock := newMockService(t)
+	skillID, certifID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectSkill(mock, skillID)
+	expectEvidenceCount(mock, "certificate", certifID, 0)
+	mock.ExpectRollback()
+
+	_, err := s.AttachCertificate(context.Background(), skillID.String(), certifID.String())
+	if got := testutil.StatusOf(err); got != http.StatusNotFound {
+		t.Fatalf("AttachCertificate() status = %d (%v), want 404", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEvidenceInvalidIds(t *testing.T) {
+	skillID := uuid.New()
+
+	tests := []struct {
+		name   string
+		change func(s *service) error
+	}{
+		{name: "attach project", change: func(s *service) error {
+			_, err := s.AttachProject(context.Background(), skillID.String(), "nope")
+			return err
+		}},
+		{name: "detach project", change: func(s *service) error {
+			_, err := s.DetachProject(context.Background(), skillID.String(), "nope")
+			return err
+		}},
+		{name: "attach certificate", change: func(s *service) error {
+			_, err := s.AttachCertificate(context.Background(), skillID.String(), "nope")
+			return err
+		}},
+		{name: "detach certificate", change: func(s *service) error {
+			_, err := s.DetachCertificate(context.Background(), skillID.String(), "nope")
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newMockService(t)
+			mock.ExpectBegin()
+			expectSkill(mock, skillID)
+			mock.ExpectRollback()
+
+			if got := testutil.StatusOf(tt.change(s)); got != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", got)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// detaching a link that does not exist is not an error
+func TestDetachCertificate(t *testing.T) {
+	s, mock := newMockService(t)
+	skillID, certifID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	expectSkill(mock, skillID)
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "certificate_skills" WHERE certificate_id = $1 AND skill_id = $2`)).
+		WithArgs(certifID, skillID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "projects"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "certificate"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	res, err := s.DetachCertificate(context.Background(), skillID.String(), certifID.String())
+	if err != nil {
+		t.Fatalf("DetachCertificate() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Certificates) != 0 {
+		t.Errorf("certificates = %+v, want none", res.Certificates)
+	}
+}
@@ -1,10 +1,33 @@
 package skill
 
 import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	"github.com/devanadindra/portfolio/back-end/utils/respond"
 )
 
 type Handler interface {
+	GetAllSkill(ctx *gin.Context)
+	GetSkillById(ctx *gin.Context)
+	CreateSkill(ctx *gin.Context)
+	UpdateSkill(ctx *gin.Context)
+	DeleteSkill(ctx *gin.Context)
+	GetSkillCategories(ctx *gin.Context)
+	CreateSkillCategory(ctx *gin.Context)
+	UpdateSkillCategory(ctx *gin.Context)
+	ReorderSkillCategories(ctx *gin.Context)
+	DeleteSkillCategory(ctx *gin.Context)
+	MoveSkills(ctx *gin.Context)
+	AttachProject(ctx *gin.Context)
+	DetachProject(ctx *gin.Context)
+	AttachCertificate(ctx *gin.Context)
+	DetachCertificate(ctx *gin.Context)
 }
 
 type handler struct {
@@ -18,3 +41,271 @@ func NewHandler(service Service, validate *validator.Validate) Handler {
 		validate: validate,
 	}
 }
+
+func (h *handler) GetAllSkill(ctx *gin.Context) {
+	filter, err := common.GetMetaData(ctx, h.validate, "name", "ratio", "experience", "created_at")
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	req := GetAllSkillReq{
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Group: ctx.Query(constants.QUERY_PARAMS_GROUP),
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	if req.Group == GROUP_CATEGORY {
+		groups, err := h.service.GetGroupedSkills(ctx, *filter)
+		if err != nil {
+			respond.Error(ctx, apierror.FromErr(err))
+			return
+		}
+
+		respond.Success(ctx, http.StatusOK, gin.H{"data": groups})
+		return
+	}
+
+	skills, total, err := h.service.GetAllSkill(ctx, req, *filter)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{
+		"data":  skills,
+		"total": total,
+		"page":  filter.Page,
+		"limit": filter.Limit,
+	})
+}
+
+func (h *handler) GetSkillById(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	res, err := h.service.GetSkillById(ctx, id)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) CreateSkill(ctx *gin.Context) {
+	var input CreateSkillReq
+	if err := ctx.ShouldBind(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	if !isValidIcon(input.Icon) {
+		respond.Error(ctx, apierror.InvalidIconFile(input.Icon.Filename))
+		return
+	}
+
+	res, err := h.service.CreateSkill(ctx, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusCreated, res)
+}
+
+func (h *handler) UpdateSkill(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input UpdateSkillReq
+	if err := ctx.ShouldBind(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	if input.Icon != nil && !isValidIcon(input.Icon) {
+		respond.Error(ctx, apierror.InvalidIconFile(input.Icon.Filename))
+		return
+	}
+
+	res, err := h.service.UpdateSkill(ctx, id, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) DeleteSkill(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := h.service.DeleteSkill(ctx, id); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "skill moved to trash"})
+}
+
+func (h *handler) GetSkillCategories(ctx *gin.Context) {
+	res, err := h.service.GetSkillCategories(ctx)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) CreateSkillCategory(ctx *gin.Context) {
+	var input CreateSkillCategoryReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	res, err := h.service.CreateSkillCategory(ctx, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusCreated, res)
+}
+
+func (h *handler) UpdateSkillCategory(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input UpdateSkillCategoryReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	res, err := h.service.UpdateSkillCategory(ctx, id, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) ReorderSkillCategories(ctx *gin.Context) {
+	var input ReorderSkillCategoriesReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	if err := h.service.ReorderSkillCategories(ctx, input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "skill categories reordered successfully"})
+}
+
+func (h *handler) DeleteSkillCategory(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := h.service.DeleteSkillCategory(ctx, id); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "skill category deleted successfully"})
+}
+
+func (h *handler) MoveSkills(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input MoveSkillsReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	res, err := h.service.MoveSkills(ctx, id, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) AttachProject(ctx *gin.Context) {
+	res, err := h.service.AttachProject(ctx, ctx.Param("id"), ctx.Param("projectId"))
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) DetachProject(ctx *gin.Context) {
+	res, err := h.service.DetachProject(ctx, ctx.Param("id"), ctx.Param("projectId"))
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) AttachCertificate(ctx *gin.Context) {
+	res, err := h.service.AttachCertificate(ctx, ctx.Param("id"), ctx.Param("certifId"))
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) DetachCertificate(ctx *gin.Context) {
+	res, err := h.service.DetachCertificate(ctx, ctx.Param("id"), ctx.Param("certifId"))
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
@@ -1,20 +1,50 @@
 package skill
 
-type LatihanReq struct {
-	Name        string   `json:"name" form:"name" binding:"required"`
-	SoalLatihan []string `json:"soal_latihan" form:"soal_latihan" binding:"required"`
-}
+import (
+	"mime/multipart"
+	"time"
 
-type DeleteLatihanReq struct {
-	ID string `json:"id" binding:"required"`
-}
+	"github.com/google/uuid"
+)
 
-type GetAllLatihanReq struct {
+type GetAllSkillReq struct {
 	Page  int64
 	Limit int64
+	// "category" returns every skill grouped by category instead of a page
+	Group string `validate:"omitempty,oneof=category"`
+}
+
+type CreateSkillReq struct {
+	Name       string                `form:"name" validate:"required,max=255"`
+	Ratio      int                   `form:"ratio" validate:"min=0,max=100"`
+	StartedAt  time.Time             `form:"started_at" time_format:"2006-01-02" validate:"required"`
+	EndedAt    *time.Time            `form:"ended_at" time_format:"2006-01-02"`
+	CategoryID string                `form:"category_id" validate:"required,uuid"`
+	Icon       *multipart.FileHeader `form:"icon" validate:"required"`
+}
+
+type UpdateSkillReq struct {
+	Name      *string    `form:"name" validate:"omitempty,min=1,max=255"`
+	Ratio     *int       `form:"ratio" validate:"omitempty,min=0,max=100"`
+	StartedAt *time.Time `form:"started_at" time_format:"2006-01-02"`
+	// an empty value marks the skill as still in use
+	EndedAt    *time.Time            `form:"ended_at" time_format:"2006-01-02"`
+	CategoryID *string               `form:"category_id" validate:"omitempty,uuid"`
+	Icon       *multipart.FileHeader `form:"icon"`
+}
+
+type CreateSkillCategoryReq struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type UpdateSkillCategoryReq struct {
+	Name *string `json:"name" validate:"omitempty,min=1,max=100"`
+}
+
+type ReorderSkillCategoriesReq struct {
+	CategoryIDs []uuid.UUID `json:"category_ids" validate:"required,gt=0"`
 }
 
-type StatsLatihanReq struct {
-	LatihanID string  `json:"latihan_id" binding:"required"`
-	Score     float64 `json:"score" binding:"required"`
+type MoveSkillsReq struct {
+	SkillIDs []uuid.UUID `json:"skill_ids" validate:"required,gt=0"`
 }
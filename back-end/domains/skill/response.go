@@ -1,29 +1,124 @@
 package skill
 
-type LatihanRes struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	TotalSoal int    `json:"total_soal"`
-	IsDone    bool   `json:"is_done"`
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/devanadindra/portfolio/back-end/utils/common"
+)
+
+type SkillRes struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	Ratio     int        `json:"ratio"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	// computed at request time, experience is expressed in period (MONTHS or YEARS)
+	ExperienceMonths int       `json:"experience_months"`
+	ExperienceLabel  string    `json:"experience_label"`
+	Experience       int       `json:"experience"`
+	Period           string    `json:"period"`
+	ImgUrl           string    `json:"img_url"`
+	CategoryID       uuid.UUID `json:"category_id"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func ToSkillRes(sk Skill) SkillRes {
+	months := experienceMonths(sk.StartedAt, sk.EndedAt, time.Now())
+
+	return SkillRes{
+		ID:               sk.ID,
+		Name:             sk.Name,
+		Ratio:            sk.Ratio,
+		StartedAt:        sk.StartedAt,
+		EndedAt:          sk.EndedAt,
+		ExperienceMonths: months,
+		ExperienceLabel:  experienceLabel(months),
+		Experience:       common.Ternary(months >= 12, months/12, months),
+		Period:           common.Ternary(months >= 12, PERIOD_YEARS, PERIOD_MONTHS),
+		ImgUrl:           sk.ImgUrl,
+		CategoryID:       sk.CategoryID,
+		CreatedAt:        sk.CreatedAt,
+		UpdatedAt:        sk.UpdatedAt,
+	}
+}
+
+type SkillCategoryRes struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Position int       `json:"position"`
+}
+
+type SkillGroupRes struct {
+	SkillCategoryRes
+	Skills []SkillRes `json:"skills"`
+}
+
+func ToSkillCategoryRes(c SkillCategory) SkillCategoryRes {
+	return SkillCategoryRes{
+		ID:       c.ID,
+		Name:     c.Name,
+		Position: c.Position,
+	}
+}
+
+// GroupSkills puts skills under their category, keeping the order of both slices.
+// Categories without skills are left out.
+func GroupSkills(categories []SkillCategory, skills []Skill) []SkillGroupRes {
+	byCategory := make(map[uuid.UUID][]SkillRes, len(categories))
+	for _, sk := range skills {
+		byCategory[sk.CategoryID] = append(byCategory[sk.CategoryID], ToSkillRes(sk))
+	}
+
+	res := make([]SkillGroupRes, 0, len(categories))
+	for _, c := range categories {
+		if len(byCategory[c.ID]) == 0 {
+			continue
+		}
+		res = append(res, SkillGroupRes{
+			SkillCategoryRes: ToSkillCategoryRes(c),
+			Skills:           byCategory[c.ID],
+		})
+	}
+	return res
+}
+
+// SkillSummaryRes is how a skill is listed on the projects and certificates backing it.
+type SkillSummaryRes struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	ImgUrl string    `json:"img_url"`
+}
+
+func ToSkillSummaries(skills []Skill) []SkillSummaryRes {
+	res := make([]SkillSummaryRes, len(skills))
+	for i, sk := range skills {
+		res[i] = SkillSummaryRes{
+			ID:     sk.ID,
+			Name:   sk.Name,
+			ImgUrl: sk.ImgUrl,
+		}
+	}
+	return res
 }
 
-type LatihanByIdRes struct {
-	ID          string           `json:"id"`
-	Name        string           `json:"name"`
-	TotalSoal   int              `json:"total_soal"`
-	SoalLatihan []SoalLatihanRes `json:"soal_latihan"`
+type SkillDetailRes struct {
+	SkillRes
+	Projects     []SkillProjectRes     `json:"projects"`
+	Certificates []SkillCertificateRes `json:"certificates"`
 }
 
-type SoalLatihanRes struct {
-	ID        string `json:"id"`
-	LatihanID string `json:"latihan_id"`
-	Soal      string `json:"soal"`
+type SkillProjectRes struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Slug string    `json:"slug"`
 }
 
-type StatsLatihanRes struct {
-	ID          string  `json:"id"`
-	LatihanID   string  `json:"latihan_id"`
-	LatihanName string  `json:"latihan_name"`
-	TotalSoal   int     `json:"total_soal"`
-	Score       float64 `json:"score"`
+type SkillCertificateRes struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Issuer string    `json:"issuer"`
+	ImgUrl string    `json:"img_url"`
 }
@@ -1,12 +1,37 @@
 package skill
 
 import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
 	"github.com/devanadindra/portfolio/back-end/database"
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
 	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
 	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
 )
 
 type Service interface {
+	GetAllSkill(ctx context.Context, input GetAllSkillReq, filter constants.FilterReq) (*[]SkillRes, int64, error)
+	GetGroupedSkills(ctx context.Context, filter constants.FilterReq) (*[]SkillGroupRes, error)
+	GetSkillById(ctx context.Context, skillId string) (*SkillDetailRes, error)
+	CreateSkill(ctx context.Context, input CreateSkillReq) (*SkillRes, error)
+	UpdateSkill(ctx context.Context, skillId string, input UpdateSkillReq) (*SkillRes, error)
+	DeleteSkill(ctx context.Context, skillId string) error
+	GetSkillCategories(ctx context.Context) (*[]SkillCategoryRes, error)
+	CreateSkillCategory(ctx context.Context, input CreateSkillCategoryReq) (*SkillCategoryRes, error)
+	UpdateSkillCategory(ctx context.Context, categoryId string, input UpdateSkillCategoryReq) (*SkillCategoryRes, error)
+	ReorderSkillCategories(ctx context.Context, input ReorderSkillCategoriesReq) error
+	DeleteSkillCategory(ctx context.Context, categoryId string) error
+	MoveSkills(ctx context.Context, categoryId string, input MoveSkillsReq) (*SkillGroupRes, error)
+	AttachProject(ctx context.Context, skillId string, projectId string) (*SkillDetailRes, error)
+	DetachProject(ctx context.Context, skillId string, projectId string) (*SkillDetailRes, error)
+	AttachCertificate(ctx context.Context, skillId string, certifId string) (*SkillDetailRes, error)
+	DetachCertificate(ctx context.Context, skillId string, certifId string) (*SkillDetailRes, error)
 }
 
 type service struct {
@@ -24,3 +49,164 @@ func NewService(config *config.Config, dbSelector *dbselector.DBService, Visitor
 		OwnerDB:    OwnerDB,
 	}
 }
+
+func (s *service) GetAllSkill(ctx context.Context, input GetAllSkillReq, filter constants.FilterReq) (*[]SkillRes, int64, error) {
+	var total int64
+	var skills []Skill
+
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := db.WithContext(ctx).
+		Model(&Skill{}).
+		Scopes(common.FilterScope(filter, "name"))
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// experience is not stored, order by the span it is computed from
+	if filter.OrderBy == "experience" {
+		filter.OrderBy = experienceOrder
+	}
+
+	// scopes run after the chained clauses, so the name tie break has to be a scope as well
+	// or it would come before the requested order
+	if err := query.
+		Scopes(common.PaginateScope(filter), OrderedSkills).
+		Find(&skills).Error; err != nil {
+		return nil, 0, err
+	}
+
+	res := make([]SkillRes, len(skills))
+	for i, sk := range skills {
+		res[i] = ToSkillRes(sk)
+	}
+
+	return &res, total, nil
+}
+
+func (s *service) CreateSkill(ctx context.Context, input CreateSkillReq) (*SkillRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sk := Skill{
+		ID:        uuid.New(),
+		Name:      input.Name,
+		Ratio:     input.Ratio,
+		StartedAt: input.StartedAt,
+		EndedAt:   input.EndedAt,
+	}
+
+	if err := validateSkillDates(sk.StartedAt, sk.EndedAt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	sk.AboutID, err = findAboutId(db.WithContext(ctx))
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	category, err := findCategory(db.WithContext(ctx), input.CategoryID)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+	sk.CategoryID = category.ID
+
+	sk.ImgUrl, err = saveSkillIcon(ctx, sk.ID, input.Icon)
+	if err != nil {
+		removeSkillFiles(ctx, sk.ImgUrl)
+		return nil, apierror.FromErr(err)
+	}
+
+	if err := db.WithContext(ctx).Create(&sk).Error; err != nil {
+		removeSkillFiles(ctx, sk.ImgUrl)
+		return nil, apierror.FromErr(err)
+	}
+
+	res := ToSkillRes(sk)
+	return &res, nil
+}
+
+func (s *service) UpdateSkill(ctx context.Context, skillId string, input UpdateSkillReq) (*SkillRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var sk Skill
+	var newUrl, oldUrl string
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		sk, err = findSkill(tx, skillId)
+		if err != nil {
+			return err
+		}
+
+		if input.Name != nil {
+			sk.Name = *input.Name
+		}
+		if input.Ratio != nil {
+			sk.Ratio = *input.Ratio
+		}
+		if input.StartedAt != nil {
+			sk.StartedAt = *input.StartedAt
+		}
+		if input.EndedAt != nil {
+			sk.EndedAt = common.Ternary(input.EndedAt.IsZero(), nil, input.EndedAt)
+		}
+		if err := validateSkillDates(sk.StartedAt, sk.EndedAt, time.Now()); err != nil {
+			return err
+		}
+		if input.CategoryID != nil {
+			category, err := findCategory(tx, *input.CategoryID)
+			if err != nil {
+				return err
+			}
+			sk.CategoryID = category.ID
+		}
+
+		if input.Icon != nil {
+			newUrl, err = saveSkillIcon(ctx, sk.ID, input.Icon)
+			if err != nil {
+				return err
+			}
+			oldUrl = sk.ImgUrl
+			sk.ImgUrl = newUrl
+		}
+
+		return tx.Save(&sk).Error
+	})
+	if err != nil {
+		removeSkillFiles(ctx, newUrl)
+		return nil, apierror.FromErr(err)
+	}
+
+	// the old icon is only removed once the new one is committed
+	removeSkillFiles(ctx, oldUrl)
+
+	res := ToSkillRes(sk)
+	return &res, nil
+}
+
+// DeleteSkill moves the skill to the trash, its icon is removed when the trash is purged.
+func (s *service) DeleteSkill(ctx context.Context, skillId string) error {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return apierror.FromErr(err)
+	}
+
+	sk, err := findSkill(db.WithContext(ctx), skillId)
+	if err != nil {
+		return apierror.FromErr(err)
+	}
+
+	if err := db.WithContext(ctx).Delete(&sk).Error; err != nil {
+		return apierror.FromErr(err)
+	}
+
+	return nil
+}
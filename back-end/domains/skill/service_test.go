@@ -0,0 +1,225 @@
+package skill
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+func newMockService(t *testing.T) (*service, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock := testutil.NewMockDB(t)
+	return &service{
+		dbSelector: dbselector.NewDBService(&database.OwnerDB{DB: db}, &database.VisitorsDB{DB: db}),
+	}, mock
+}
+
+func uploadedIcons(t *testing.T) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(skillUploadDir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func expectAbout(mock sqlmock.Sqlmock, aboutID uuid.UUID) {
+	rows := sqlmock.NewRows([]string{"id"})
+	if aboutID != uuid.Nil {
+		rows.AddRow(aboutID)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "about" ORDER BY created_at ASC LIMIT $1`)).
+		WithArgs(1).
+		WillReturnRows(rows)
+}
+
+func newSkillReq(t *testing.T, categoryID uuid.UUID) CreateSkillReq {
+	return CreateSkillReq{
+		Name:       "Go",
+		Ratio:      80,
+		StartedAt:  time.Now().AddDate(-2, 0, 0),
+		CategoryID: categoryID.String(),
+		Icon:       testutil.FormFile(t, "go.SVG", []byte("<svg/>")),
+	}
+}
+
+// the skill belongs to the about row without the client having to know its id
+func TestCreateSkill(t *testing.T) {
+	t.Chdir(t.TempDir())
+	s, mock := newMockService(t)
+	aboutID, categoryID := uuid.New(), uuid.New()
+
+	expectAbout(mock, aboutID)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skill_categories" WHERE id = $1`)).
+		WithArgs(categoryID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(categoryID, "Backend"))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "skills" ("about_id","category_id","name"`)).
+		WithArgs(aboutID, categoryID, "Go", 80, sqlmock.AnyArg(), nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	res, err := s.CreateSkill(context.Background(), newSkillReq(t, categoryID))
+	if err != nil {
+		t.Fatalf("CreateSkill() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Ext(res.ImgUrl) != ".svg" || !fileExists(res.ImgUrl[1:]) {
+		t.Errorf("icon %q was not stored", res.ImgUrl)
+	}
+	if res.ExperienceMonths != 24 {
+		t.Errorf("experience = %d months, want 24", res.ExperienceMonths)
+	}
+}
+
+func TestCreateSkillWithoutAbout(t *testing.T) {
+	t.Chdir(t.TempDir())
+	s, mock := newMockService(t)
+
+	expectAbout(mock, uuid.Nil)
+
+	_, err := s.CreateSkill(context.Background(), newSkillReq(t, uuid.New()))
+	if got := testutil.StatusOf(err); got != http.StatusNotFound {
+		t.Fatalf("CreateSkill() status = %d (%v), want 404", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if files := uploadedIcons(t); len(files) != 0 {
+		t.Errorf("icons written = %v, want none", files)
+	}
+}
+
+func TestCreateSkillRemovesIconOnError(t *testing.T) {
+	t.Chdir(t.TempDir())
+	s, mock := newMockService(t)
+	categoryID := uuid.New()
+
+	expectAbout(mock, uuid.New())
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skill_categories" WHERE id = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(categoryID, "Backend"))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "skills"`)).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	if _, err := s.CreateSkill(context.Background(), newSkillReq(t, categoryID)); err == nil {
+		t.Fatal("CreateSkill() succeeded")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if files := uploadedIcons(t); len(files) != 0 {
+		t.Errorf("icons left behind = %v", files)
+	}
+}
+
+// the old icon is only removed once the new one is committed
+func TestUpdateSkillReplacesIcon(t *testing.T) {
+	t.Chdir(t.TempDir())
+	s, mock := newMockService(t)
+	skillID := uuid.New()
+
+	oldIcon := "/" + skillUploadDir + "/old.png"
+	if err := os.MkdirAll(skillUploadDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(oldIcon[1:], []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skills" WHERE id = $1`)).
+		WithArgs(skillID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "started_at", "img_url"}).
+			AddRow(skillID, "Go", time.Now().AddDate(-1, 0, 0), oldIcon))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "skills" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	res, err := s.UpdateSkill(context.Background(), skillID.String(), UpdateSkillReq{Icon: testutil.FormFile(t, "new.svg", []byte("<svg/>"))})
+	if err != nil {
+		t.Fatalf("UpdateSkill() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.ImgUrl == oldIcon || !fileExists(res.ImgUrl[1:]) {
+		t.Errorf("new icon %q was not stored", res.ImgUrl)
+	}
+	if fileExists(oldIcon[1:]) {
+		t.Error("old icon was not removed")
+	}
+}
+
+func TestUpdateSkillRollbackKeepsOldIcon(t *testing.T) {
+	t.Chdir(t.TempDir())
+	s, mock := newMockService(t)
+	skillID := uuid.New()
+	oldIcon := "/" + skillUploadDir + "/old.png"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skills" WHERE id = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "started_at", "img_url"}).
+			AddRow(skillID, "Go", time.Now().AddDate(-1, 0, 0), oldIcon))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "skills" SET`)).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	if _, err := s.UpdateSkill(context.Background(), skillID.String(), UpdateSkillReq{Icon: testutil.FormFile(t, "new.svg", []byte("<svg/>"))}); err == nil {
+		t.Fatal("UpdateSkill() succeeded")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if files := uploadedIcons(t); len(files) != 0 {
+		t.Errorf("icons left behind = %v", files)
+	}
+}
+
+// experience is not a column, ordering by it uses the span it is computed from. The name
+// only breaks ties.
+func TestGetAllSkillOrderByExperience(t *testing.T) {
+	s, mock := newMockService(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "skills" WHERE "skills"."deleted_at" IS NULL`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skills" WHERE "skills"."deleted_at" IS NULL ORDER BY ` + experienceOrder + ` desc,name ASC LIMIT $1`)).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(uuid.New(), "Go"))
+
+	res, total, err := s.GetAllSkill(context.Background(), GetAllSkillReq{}, constants.FilterReq{Limit: 10, Page: 1, OrderBy: "experience", SortOrder: "desc"})
+	if err != nil {
+		t.Fatalf("GetAllSkill() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(*res) != 1 {
+		t.Errorf("got %d of %d skills, want 1 of 1", len(*res), total)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
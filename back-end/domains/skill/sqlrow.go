@@ -4,20 +4,58 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Skill struct {
 	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	AboutID    uuid.UUID `gorm:"type:uuid;not null"`
+	CategoryID uuid.UUID `gorm:"type:uuid;not null"`
 	Name       string
 	Ratio      int
-	Experience int
-	Period     string
-	ImgUrl     string
-	CreatedAt  time.Time `gorm:"autoCreateTime"`
-	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+	StartedAt  time.Time `gorm:"type:date"`
+	// nil while the skill is still in use
+	EndedAt   *time.Time `gorm:"type:date"`
+	ImgUrl    string
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt
 }
 
 func (Skill) TableName() string {
 	return "skills"
 }
+
+type SkillCategory struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Name      string
+	Position  int
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (SkillCategory) TableName() string {
+	return "skill_categories"
+}
+
+// ProjectSkill links a skill to a project demonstrating it.
+type ProjectSkill struct {
+	ProjectID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SkillID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (ProjectSkill) TableName() string {
+	return "project_skills"
+}
+
+// CertificateSkill links a skill to a certificate proving it.
+type CertificateSkill struct {
+	CertificateID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SkillID       uuid.UUID `gorm:"type:uuid;primaryKey"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+func (CertificateSkill) TableName() string {
+	return "certificate_skills"
+}
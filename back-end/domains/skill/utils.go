@@ -0,0 +1,184 @@
+package skill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	fileutils "github.com/devanadindra/portfolio/back-end/utils/file"
+	"github.com/devanadindra/portfolio/back-end/utils/logger"
+)
+
+const skillUploadDir = "uploads/skill"
+
+// isValidIcon accepts the usual images plus svg, which most skill logos come as.
+func isValidIcon(file *multipart.FileHeader) bool {
+	return fileutils.IsValidImage(file) || strings.ToLower(filepath.Ext(file.Filename)) == ".svg"
+}
+
+// saveSkillIcon writes the uploaded icon to disk and returns its public url.
+func saveSkillIcon(ctx context.Context, skillID uuid.UUID, file *multipart.FileHeader) (string, error) {
+	if err := os.MkdirAll(skillUploadDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	filename, err := fileutils.GenerateMediaName(skillID.String())
+	if err != nil {
+		return "", err
+	}
+
+	filename += strings.ToLower(filepath.Ext(file.Filename))
+	url := "/" + skillUploadDir + "/" + filename
+	if err := fileutils.SaveMedia(ctx, file, filepath.Join(skillUploadDir, filename)); err != nil {
+		// the url is still returned so the caller can clean up a partially written file
+		return url, err
+	}
+
+	return url, nil
+}
+
+// removeSkillFiles deletes files from disk, logging failures instead of returning them
+// because it is only used for cleanup after the database work has been decided.
+func removeSkillFiles(ctx context.Context, urls ...string) {
+	for _, url := range urls {
+		if err := fileutils.RemoveMedia(url); err != nil {
+			logger.Error(ctx, "%v", err)
+		}
+	}
+}
+
+func findSkill(tx *gorm.DB, skillId string) (Skill, error) {
+	var sk Skill
+
+	id, err := uuid.Parse(skillId)
+	if err != nil {
+		return sk, apierror.InvalidSkillId()
+	}
+
+	if err := tx.First(&sk, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return sk, apierror.SkillNotFound(skillId)
+		}
+		return sk, err
+	}
+
+	return sk, nil
+}
+
+// findAboutId returns the id of the about row every skill belongs to. The portfolio has a
+// single about row, the oldest one wins should there ever be more.
+func findAboutId(tx *gorm.DB) (uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := tx.Table("about").
+		Order("created_at ASC").
+		Limit(1).
+		Pluck("id", &ids).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	if len(ids) == 0 {
+		return uuid.Nil, apierror.AboutNotFound()
+	}
+
+	return ids[0], nil
+}
+
+func findCategory(tx *gorm.DB, categoryId string) (SkillCategory, error) {
+	var category SkillCategory
+
+	id, err := uuid.Parse(categoryId)
+	if err != nil {
+		return category, apierror.InvalidSkillCategoryId()
+	}
+
+	if err := tx.First(&category, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return category, apierror.SkillCategoryNotFound(categoryId)
+		}
+		return category, err
+	}
+
+	return category, nil
+}
+
+// ensureCategoryNameFree compares names case-insensitively so "Tools" and "tools" cannot coexist.
+func ensureCategoryNameFree(tx *gorm.DB, name string, categoryID uuid.UUID) error {
+	var count int64
+	if err := tx.Model(&SkillCategory{}).
+		Where("LOWER(name) = LOWER(?) AND id <> ?", name, categoryID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return apierror.DuplicateSkillCategory(name)
+	}
+
+	return nil
+}
+
+// experienceMonths counts the whole months from startedAt to endedAt, or to now while the
+// skill is still in use.
+func experienceMonths(startedAt time.Time, endedAt *time.Time, now time.Time) int {
+	end := now
+	if endedAt != nil {
+		end = *endedAt
+	}
+
+	months := (end.Year()-startedAt.Year())*12 + int(end.Month()-startedAt.Month())
+	if end.Day() < startedAt.Day() {
+		months--
+	}
+
+	return max(months, 0)
+}
+
+// experienceLabel renders a month count like "2 years 3 months".
+func experienceLabel(months int) string {
+	if months == 0 {
+		return "less than a month"
+	}
+
+	years, rest := months/12, months%12
+	parts := make([]string, 0, 2)
+	if years > 0 {
+		parts = append(parts, pluralize(years, "year"))
+	}
+	if rest > 0 {
+		parts = append(parts, pluralize(rest, "month"))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func pluralize(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// validateSkillDates rejects skills starting in the future or ending before they started.
+func validateSkillDates(startedAt time.Time, endedAt *time.Time, now time.Time) error {
+	if startedAt.After(now) {
+		return apierror.InvalidSkillDates("started_at must not be in the future")
+	}
+	if endedAt != nil && endedAt.Before(startedAt) {
+		return apierror.InvalidSkillDates("ended_at must not be before started_at")
+	}
+	return nil
+}
+
+// OrderedSkills is used with Preload("Skills", ...) on projects and certificates.
+func OrderedSkills(db *gorm.DB) *gorm.DB {
+	return db.Order("name ASC")
+}
@@ -0,0 +1,71 @@
+package skill
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestExperienceMonths(t *testing.T) {
+	now := date(2024, time.June, 15)
+	ended := date(2023, time.March, 1)
+
+	tests := []struct {
+		name      string
+		startedAt time.Time
+		endedAt   *time.Time
+		want      int
+	}{
+		{name: "still in use", startedAt: date(2022, time.January, 10), want: 29},
+		{name: "month not complete yet", startedAt: date(2024, time.May, 20), want: 0},
+		{name: "month just complete", startedAt: date(2024, time.May, 15), want: 1},
+		{name: "ended", startedAt: date(2021, time.March, 1), endedAt: &ended, want: 24},
+		{name: "started today", startedAt: now, want: 0},
+		{name: "ended before it started", startedAt: date(2023, time.June, 1), endedAt: &ended, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := experienceMonths(tt.startedAt, tt.endedAt, now); got != tt.want {
+			t.Errorf("%s: experienceMonths() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExperienceLabel(t *testing.T) {
+	tests := map[int]string{
+		0:  "less than a month",
+		1:  "1 month",
+		5:  "5 months",
+		12: "1 year",
+		13: "1 year 1 month",
+		27: "2 years 3 months",
+		36: "3 years",
+	}
+
+	for months, want := range tests {
+		if got := experienceLabel(months); got != want {
+			t.Errorf("experienceLabel(%d) = %q, want %q", months, got, want)
+		}
+	}
+}
+
+func TestValidateSkillDates(t *testing.T) {
+	now := date(2024, time.June, 15)
+	before := date(2020, time.January, 1)
+
+	if err := validateSkillDates(date(2021, time.January, 1), nil, now); err != nil {
+		t.Errorf("valid dates refused: %v", err)
+	}
+	if err := validateSkillDates(now.AddDate(0, 0, 1), nil, now); testutil.StatusOf(err) != http.StatusBadRequest {
+		t.Errorf("future start: err = %v, want 400", err)
+	}
+	if err := validateSkillDates(date(2021, time.January, 1), &before, now); testutil.StatusOf(err) != http.StatusBadRequest {
+		t.Errorf("end before start: err = %v, want 400", err)
+	}
+}
@@ -0,0 +1,95 @@
+package tag
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/respond"
+)
+
+type Handler interface {
+	GetAllTags(ctx *gin.Context)
+	CreateTag(ctx *gin.Context)
+	UpdateTag(ctx *gin.Context)
+	DeleteTag(ctx *gin.Context)
+}
+
+type handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service, validate *validator.Validate) Handler {
+	return &handler{
+		service:  service,
+		validate: validate,
+	}
+}
+
+func (h *handler) GetAllTags(ctx *gin.Context) {
+	res, err := h.service.GetAllTags(ctx)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) CreateTag(ctx *gin.Context) {
+	var input CreateTagReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	res, err := h.service.CreateTag(ctx, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusCreated, res)
+}
+
+func (h *handler) UpdateTag(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input UpdateTagReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	res, err := h.service.UpdateTag(ctx, id, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) DeleteTag(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := h.service.DeleteTag(ctx, id); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "tag deleted successfully"})
+}
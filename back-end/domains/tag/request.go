@@ -0,0 +1,12 @@
+package tag
+
+type CreateTagReq struct {
+	Name    string  `json:"name" validate:"required,max=100"`
+	SkillID *string `json:"skill_id" validate:"omitempty,uuid"`
+}
+
+type UpdateTagReq struct {
+	Name *string `json:"name" validate:"omitempty,min=1,max=100"`
+	// an empty string unlinks the tag from its skill
+	SkillID *string `json:"skill_id" validate:"omitempty,uuid|eq="`
+}
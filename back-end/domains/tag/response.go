@@ -0,0 +1,19 @@
+package tag
+
+import "github.com/google/uuid"
+
+type TagRes struct {
+	ID      uuid.UUID  `json:"id"`
+	Name    string     `json:"name"`
+	Slug    string     `json:"slug"`
+	SkillID *uuid.UUID `json:"skill_id"`
+}
+
+func ToTagRes(t Tag) TagRes {
+	return TagRes{
+		ID:      t.ID,
+		Name:    t.Name,
+		Slug:    t.Slug,
+		SkillID: t.SkillID,
+	}
+}
@@ -0,0 +1,203 @@
+package tag
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/domains/skill"
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+)
+
+type Service interface {
+	GetAllTags(ctx context.Context) (*[]TagRes, error)
+	CreateTag(ctx context.Context, input CreateTagReq) (*TagRes, error)
+	UpdateTag(ctx context.Context, tagId string, input UpdateTagReq) (*TagRes, error)
+	DeleteTag(ctx context.Context, tagId string) error
+}
+
+type service struct {
+	authConfig config.Auth
+	dbSelector *dbselector.DBService
+	VisitorsDB *database.VisitorsDB
+	OwnerDB    *database.OwnerDB
+}
+
+func NewService(config *config.Config, dbSelector *dbselector.DBService, VisitorsDB *database.VisitorsDB, OwnerDB *database.OwnerDB) Service {
+	return &service{
+		authConfig: config.Auth,
+		dbSelector: dbSelector,
+		VisitorsDB: VisitorsDB,
+		OwnerDB:    OwnerDB,
+	}
+}
+
+func (s *service) GetAllTags(ctx context.Context) (*[]TagRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+	if err := db.WithContext(ctx).Order("name ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]TagRes, len(tags))
+	for i, t := range tags {
+		res[i] = ToTagRes(t)
+	}
+
+	return &res, nil
+}
+
+func (s *service) CreateTag(ctx context.Context, input CreateTagReq) (*TagRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := Tag{
+		ID:   uuid.New(),
+		Name: input.Name,
+		Slug: common.Slugify(input.Name),
+	}
+	if tag.Slug == "" {
+		return nil, apierror.InvalidTagName(input.Name)
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureSlugFree(tx, tag.Slug, tag.ID); err != nil {
+			return err
+		}
+
+		if input.SkillID != nil {
+			tag.SkillID, err = findSkillId(tx, *input.SkillID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(&tag).Error
+	})
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	res := ToTagRes(tag)
+	return &res, nil
+}
+
+func (s *service) UpdateTag(ctx context.Context, tagId string, input UpdateTagReq) (*TagRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag Tag
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tag, err = findTag(tx, tagId)
+		if err != nil {
+			return err
+		}
+
+		if input.Name != nil {
+			slug := common.Slugify(*input.Name)
+			if slug == "" {
+				return apierror.InvalidTagName(*input.Name)
+			}
+			if err := ensureSlugFree(tx, slug, tag.ID); err != nil {
+				return err
+			}
+			tag.Name = *input.Name
+			tag.Slug = slug
+		}
+
+		if input.SkillID != nil {
+			tag.SkillID = nil
+			if *input.SkillID != "" {
+				tag.SkillID, err = findSkillId(tx, *input.SkillID)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Save(&tag).Error
+	})
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	res := ToTagRes(tag)
+	return &res, nil
+}
+
+func (s *service) DeleteTag(ctx context.Context, tagId string) error {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return err
+	}
+
+	tag, err := findTag(db.WithContext(ctx), tagId)
+	if err != nil {
+		return apierror.FromErr(err)
+	}
+
+	if err := db.WithContext(ctx).Delete(&tag).Error; err != nil {
+		return apierror.FromErr(err)
+	}
+
+	return nil
+}
+
+func findTag(tx *gorm.DB, tagId string) (Tag, error) {
+	var tag Tag
+
+	id, err := uuid.Parse(tagId)
+	if err != nil {
+		return tag, apierror.InvalidTagId()
+	}
+
+	if err := tx.First(&tag, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tag, apierror.TagNotFound(tagId)
+		}
+		return tag, err
+	}
+
+	return tag, nil
+}
+
+func ensureSlugFree(tx *gorm.DB, slug string, tagID uuid.UUID) error {
+	var count int64
+	if err := tx.Model(&Tag{}).
+		Where("slug = ? AND id <> ?", slug, tagID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return apierror.DuplicateTag(slug)
+	}
+
+	return nil
+}
+
+func findSkillId(tx *gorm.DB, skillId string) (*uuid.UUID, error) {
+	var sk skill.Skill
+	if err := tx.Select("id").First(&sk, "id = ?", skillId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apierror.SkillNotFound(skillId)
+		}
+		return nil, err
+	}
+
+	return &sk.ID, nil
+}
@@ -0,0 +1,20 @@
+package tag
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Tag struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Name      string
+	Slug      string     `gorm:"unique"`
+	SkillID   *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
@@ -0,0 +1,8 @@
+package trash
+
+const (
+	TYPE_PROJECT       = "project"
+	TYPE_PROJECT_IMAGE = "project_image"
+	TYPE_CERTIFICATE   = "certificate"
+	TYPE_SKILL         = "skill"
+)
@@ -0,0 +1,50 @@
+package trash
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/respond"
+)
+
+type Handler interface {
+	GetTrash(ctx *gin.Context)
+	Restore(ctx *gin.Context)
+}
+
+type handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service, validate *validator.Validate) Handler {
+	return &handler{
+		service:  service,
+		validate: validate,
+	}
+}
+
+func (h *handler) GetTrash(ctx *gin.Context) {
+	res, err := h.service.GetTrash(ctx)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) Restore(ctx *gin.Context) {
+	itemType := ctx.Param("type")
+	id := ctx.Param("id")
+
+	if err := h.service.Restore(ctx, itemType, id); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{"message": itemType + " restored successfully"})
+}
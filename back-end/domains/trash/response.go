@@ -0,0 +1,16 @@
+package trash
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashRes struct {
+	Type      string     `json:"type"`
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	ParentID  *uuid.UUID `json:"parent_id"`
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   time.Time  `json:"purge_at"`
+}
@@ -0,0 +1,296 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/domains/certif"
+	"github.com/devanadindra/portfolio/back-end/domains/project"
+	"github.com/devanadindra/portfolio/back-end/domains/skill"
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+	fileutils "github.com/devanadindra/portfolio/back-end/utils/file"
+	"github.com/devanadindra/portfolio/back-end/utils/logger"
+	"github.com/devanadindra/portfolio/back-end/utils/scheduler"
+)
+
+type Service interface {
+	GetTrash(ctx context.Context) (*[]TrashRes, error)
+	Restore(ctx context.Context, itemType string, id string) error
+	Purge(ctx context.Context) error
+	PurgeJob() scheduler.Job
+}
+
+type service struct {
+	trashConfig config.Trash
+	dbSelector  *dbselector.DBService
+	VisitorsDB  *database.VisitorsDB
+	OwnerDB     *database.OwnerDB
+}
+
+func NewService(config *config.Config, dbSelector *dbselector.DBService, VisitorsDB *database.VisitorsDB, OwnerDB *database.OwnerDB) Service {
+	return &service{
+		trashConfig: config.Trash,
+		dbSelector:  dbSelector,
+		VisitorsDB:  VisitorsDB,
+		OwnerDB:     OwnerDB,
+	}
+}
+
+func (s *service) GetTrash(ctx context.Context) (*[]TrashRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	db = db.WithContext(ctx).Unscoped().Session(&gorm.Session{})
+
+	res := []TrashRes{}
+
+	var projects []project.Projects
+	if err := db.Where("deleted_at IS NOT NULL").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		res = append(res, s.toTrashRes(TYPE_PROJECT, p.ID, p.Name, nil, p.DeletedAt))
+	}
+
+	// images of a trashed project come back with the project, only list the ones deleted on their own
+	var images []project.ProjectImages
+	if err := db.Where("deleted_at IS NOT NULL").
+		Where("project_id IN (?)", db.Model(&project.Projects{}).Select("id").Where("deleted_at IS NULL")).
+		Find(&images).Error; err != nil {
+		return nil, err
+	}
+	for _, img := range images {
+		projectID := img.ProjectID
+		res = append(res, s.toTrashRes(TYPE_PROJECT_IMAGE, img.ID, img.ImgUrl, &projectID, img.DeletedAt))
+	}
+
+	var certifs []certif.Certificate
+	if err := db.Where("deleted_at IS NOT NULL").Find(&certifs).Error; err != nil {
+		return nil, err
+	}
+	for _, c := range certifs {
+		res = append(res, s.toTrashRes(TYPE_CERTIFICATE, c.ID, c.Name, nil, c.DeletedAt))
+	}
+
+	var skills []skill.Skill
+	if err := db.Where("deleted_at IS NOT NULL").Find(&skills).Error; err != nil {
+		return nil, err
+	}
+	for _, sk := range skills {
+		res = append(res, s.toTrashRes(TYPE_SKILL, sk.ID, sk.Name, nil, sk.DeletedAt))
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].DeletedAt.After(res[j].DeletedAt)
+	})
+
+	return &res, nil
+}
+
+func (s *service) Restore(ctx context.Context, itemType string, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return apierror.InvalidTrashId()
+	}
+
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return err
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		switch itemType {
+		case TYPE_PROJECT:
+			return restoreProject(tx, id)
+		case TYPE_PROJECT_IMAGE:
+			return restoreProjectImage(tx, id)
+		case TYPE_CERTIFICATE:
+			err := restoreRow(tx, &certif.Certificate{}, itemType, id)
+			if common.IsUniqueViolation(err, certif.CREDENTIAL_ID_INDEX) {
+				return apierror.CertifCredentialIdTaken()
+			}
+			return err
+		case TYPE_SKILL:
+			return restoreRow(tx, &skill.Skill{}, itemType, id)
+		default:
+			return apierror.InvalidTrashType(itemType)
+		}
+	})
+}
+
+// Purge permanently removes everything that has been in the trash longer than the retention
+// period. Files are only removed from disk after the rows are gone.
+func (s *service) Purge(ctx context.Context) error {
+	cutoff := time.Now().Add(-s.trashConfig.Retention)
+
+	var urls []string
+	err := s.OwnerDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tx = tx.Unscoped().Session(&gorm.Session{})
+
+		var projectIds []uuid.UUID
+		if err := tx.Model(&project.Projects{}).
+			Where("deleted_at < ?", cutoff).
+			Pluck("id", &projectIds).Error; err != nil {
+			return err
+		}
+
+		imageQuery := tx.Where("deleted_at < ?", cutoff)
+		if len(projectIds) > 0 {
+			imageQuery = tx.Where("deleted_at < ? OR project_id IN ?", cutoff, projectIds)
+		}
+
+		var images []project.ProjectImages
+		if err := imageQuery.Find(&images).Error; err != nil {
+			return err
+		}
+		if len(images) > 0 {
+			for _, img := range images {
+				urls = append(urls, img.ImgUrl)
+			}
+			if err := tx.Delete(&images).Error; err != nil {
+				return err
+			}
+		}
+
+		// slug aliases and tag links are removed by ON DELETE CASCADE
+		if len(projectIds) > 0 {
+			if err := tx.Where("id IN ?", projectIds).Delete(&project.Projects{}).Error; err != nil {
+				return err
+			}
+		}
+
+		var certifs []certif.Certificate
+		if err := tx.Where("deleted_at < ?", cutoff).Find(&certifs).Error; err != nil {
+			return err
+		}
+		if len(certifs) > 0 {
+			for _, c := range certifs {
+				urls = append(urls, c.ImgUrl)
+				if c.PdfUrl != nil {
+					urls = append(urls, *c.PdfUrl)
+				}
+			}
+			if err := tx.Delete(&certifs).Error; err != nil {
+				return err
+			}
+		}
+
+		var skills []skill.Skill
+		if err := tx.Where("deleted_at < ?", cutoff).Find(&skills).Error; err != nil {
+			return err
+		}
+		if len(skills) > 0 {
+			for _, sk := range skills {
+				urls = append(urls, sk.ImgUrl)
+			}
+			if err := tx.Delete(&skills).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, url := range urls {
+		if url == "" {
+			continue
+		}
+		if err := fileutils.RemoveMedia(url); err != nil {
+			logger.Error(ctx, "%v", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) PurgeJob() scheduler.Job {
+	return scheduler.Job{
+		Name:     "trash-purge",
+		Interval: s.trashConfig.PurgeInterval,
+		Run:      s.Purge,
+	}
+}
+
+func (s *service) toTrashRes(itemType string, id uuid.UUID, name string, parentID *uuid.UUID, deletedAt gorm.DeletedAt) TrashRes {
+	return TrashRes{
+		Type:      itemType,
+		ID:        id,
+		Name:      name,
+		ParentID:  parentID,
+		DeletedAt: deletedAt.Time,
+		PurgeAt:   deletedAt.Time.Add(s.trashConfig.Retention),
+	}
+}
+
+// restoreProject brings back the project together with the images that were trashed with it,
+// images deleted on their own before that stay in the trash.
+func restoreProject(tx *gorm.DB, id string) error {
+	var p project.Projects
+	if err := findTrashed(tx, &p, TYPE_PROJECT, id); err != nil {
+		return err
+	}
+
+	if err := tx.Unscoped().Model(&project.ProjectImages{}).
+		Where("project_id = ? AND deleted_at = ?", p.ID, p.DeletedAt.Time).
+		Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Model(&p).Update("deleted_at", nil).Error
+}
+
+// restoreProjectImage puts the image back at the end of the gallery. It never comes back as
+// the cover because the project may have a new one by now.
+func restoreProjectImage(tx *gorm.DB, id string) error {
+	var img project.ProjectImages
+	if err := findTrashed(tx, &img, TYPE_PROJECT_IMAGE, id); err != nil {
+		return err
+	}
+
+	if err := tx.Select("id").First(&project.Projects{}, "id = ?", img.ProjectID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apierror.TrashParentDeleted(TYPE_PROJECT)
+		}
+		return err
+	}
+
+	var position int
+	if err := tx.Model(&project.ProjectImages{}).
+		Where("project_id = ?", img.ProjectID).
+		Select("COALESCE(MAX(position) + 1, 0)").
+		Scan(&position).Error; err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Model(&img).
+		Select("deleted_at", "is_cover", "position").
+		Updates(map[string]any{"deleted_at": nil, "is_cover": false, "position": position}).Error
+}
+
+func restoreRow(tx *gorm.DB, row any, itemType string, id string) error {
+	if err := findTrashed(tx, row, itemType, id); err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Model(row).Update("deleted_at", nil).Error
+}
+
+func findTrashed(tx *gorm.DB, row any, itemType string, id string) error {
+	err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apierror.TrashItemNotFound(itemType, id)
+	}
+	return err
+}
@@ -0,0 +1,193 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+func newMockService(t *testing.T) (*service, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock := testutil.NewMockDB(t)
+
+	ownerDB, visitorsDB := &database.OwnerDB{DB: db}, &database.VisitorsDB{DB: db}
+	return &service{
+		trashConfig: config.Trash{Retention: 24 * time.Hour},
+		dbSelector:  dbselector.NewDBService(ownerDB, visitorsDB),
+		VisitorsDB:  visitorsDB,
+		OwnerDB:     ownerDB,
+	}, mock
+}
+
+func TestRestoreRefused(t *testing.T) {
+	id := uuid.NewString()
+
+	tests := []struct {
+		name     string
+		itemType string
+		id       string
+		expect   func(mock sqlmock.Sqlmock)
+		status   int
+	}{
+		{name: "invalid id", itemType: TYPE_SKILL, id: "nope", status: http.StatusBadRequest},
+		{
+			name:     "unknown type",
+			itemType: "tag",
+			id:       id,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:     "not in the trash",
+			itemType: TYPE_SKILL,
+			id:       id,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skills" WHERE id = $1 AND deleted_at IS NOT NULL`)).
+					WithArgs(id, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newMockService(t)
+			if tt.expect != nil {
+				tt.expect(mock)
+			}
+
+			err := s.Restore(context.Background(), tt.itemType, tt.id)
+			if got := testutil.StatusOf(err); got != tt.status {
+				t.Fatalf("Restore() status = %d (%v), want %d", got, err, tt.status)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// only the images trashed together with the project come back with it
+func TestRestoreProject(t *testing.T) {
+	s, mock := newMockService(t)
+
+	id := uuid.New()
+	deletedAt := time.Now().Add(-time.Hour)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "projects" WHERE id = $1 AND deleted_at IS NOT NULL`)).
+		WithArgs(id.String(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "deleted_at"}).AddRow(id, deletedAt))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "project_images" SET "deleted_at"=$1,"updated_at"=$2 WHERE project_id = $3 AND deleted_at = $4`)).
+		WithArgs(nil, sqlmock.AnyArg(), id, deletedAt).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "projects" SET "deleted_at"=$1,"updated_at"=$2 WHERE "id" = $3`)).
+		WithArgs(nil, sqlmock.AnyArg(), id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := s.Restore(context.Background(), TYPE_PROJECT, id.String()); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll("uploads/certif", os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"uploads/certif/old.png", "uploads/certif/old.pdf"} {
+		if err := os.WriteFile(path, []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s, mock := newMockService(t)
+	certifId := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "projects" WHERE deleted_at < $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "project_images" WHERE deleted_at < $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "certificate" WHERE deleted_at < $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "img_url", "pdf_url"}).
+			AddRow(certifId, "/uploads/certif/old.png", "/uploads/certif/old.pdf"))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "certificate" WHERE "certificate"."id" = $1`)).
+		WithArgs(certifId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skills" WHERE deleted_at < $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	if err := s.Purge(context.Background()); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"uploads/certif/old.png", "uploads/certif/old.pdf"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed", path)
+		}
+	}
+}
+
+// files stay on disk when the rows could not be deleted
+func TestPurgeKeepsFilesOnError(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll("uploads/skill", os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("uploads/skill/icon.svg", []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, mock := newMockService(t)
+	skillId := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "projects" WHERE deleted_at < $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "project_images" WHERE deleted_at < $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "certificate" WHERE deleted_at < $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skills" WHERE deleted_at < $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "img_url"}).AddRow(skillId, "/uploads/skill/icon.svg"))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "skills"`)).
+		WillReturnError(errors.New("violates foreign key constraint"))
+	mock.ExpectRollback()
+
+	if err := s.Purge(context.Background()); err == nil {
+		t.Fatal("Purge() succeeded")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat("uploads/skill/icon.svg"); err != nil {
+		t.Errorf("the icon was removed: %v", err)
+	}
+}
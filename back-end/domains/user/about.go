@@ -0,0 +1,117 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	fileutils "github.com/devanadindra/portfolio/back-end/utils/file"
+)
+
+const aboutUploadDir = "uploads/about"
+
+// UpdateAbout changes the about page and returns it the same way GetAbout does. A new photo
+// replaces the old one, which is only removed from disk once the change is committed.
+func (s *service) UpdateAbout(ctx context.Context, input UpdateAboutReq) (*AboutRes, error) {
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil && strings.TrimSpace(*input.Name) == "" {
+		return nil, apierror.InvalidAboutName()
+	}
+
+	var res *AboutRes
+	var newUrl, oldUrl string
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		about, err := findAbout(tx)
+		if err != nil {
+			return err
+		}
+
+		if input.Name != nil {
+			about.Name = strings.TrimSpace(*input.Name)
+		}
+		if input.Nim != nil {
+			about.Nim = strings.TrimSpace(*input.Nim)
+		}
+		if input.Major != nil {
+			about.Major = *input.Major
+		}
+		if input.Faculty != nil {
+			about.Faculty = *input.Faculty
+		}
+		if input.Biography != nil {
+			about.Biography = *input.Biography
+		}
+		if input.Slogan != nil {
+			about.Slogan = *input.Slogan
+		}
+
+		if input.Photo != nil {
+			newUrl, err = saveAboutPhoto(ctx, about, input.Photo)
+			if err != nil {
+				return err
+			}
+			oldUrl = about.ImgUrl
+			about.ImgUrl = newUrl
+		}
+
+		if err := tx.Omit(clause.Associations).Save(&about).Error; err != nil {
+			return err
+		}
+
+		res, err = toAboutRes(tx, about)
+		return err
+	})
+	if err != nil {
+		removeMediaFiles(ctx, newUrl)
+		return nil, apierror.FromErr(err)
+	}
+
+	removeMediaFiles(ctx, oldUrl)
+
+	return res, nil
+}
+
+// findAbout returns the about row with its skills. The portfolio has a single about row,
+// the oldest one wins should there ever be more.
+func findAbout(tx *gorm.DB) (About, error) {
+	var about About
+	err := tx.Preload("Skills").
+		Order("created_at ASC").
+		First(&about).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return about, apierror.AboutNotFound()
+	}
+
+	return about, err
+}
+
+func saveAboutPhoto(ctx context.Context, about About, file *multipart.FileHeader) (string, error) {
+	if err := os.MkdirAll(aboutUploadDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	filename, err := fileutils.GenerateMediaName(about.ID.String())
+	if err != nil {
+		return "", err
+	}
+
+	filename += strings.ToLower(filepath.Ext(file.Filename))
+	url := "/" + aboutUploadDir + "/" + filename
+	if err := fileutils.SaveMedia(ctx, file, filepath.Join(aboutUploadDir, filename)); err != nil {
+		// the url is still returned so the caller can clean up a partially written file
+		return url, err
+	}
+
+	return url, nil
+}
@@ -0,0 +1,163 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	contextUtil "github.com/devanadindra/portfolio/back-end/utils/context"
+	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+func ownerContext(ownerId uuid.UUID) context.Context {
+	return contextUtil.SetTokenClaims(context.Background(), constants.Token{
+		Claims: constants.JWTClaims{UserID: ownerId, Role: constants.OWNER},
+	})
+}
+
+func newOwnerService(db *gorm.DB) *service {
+	ownerDB, visitorsDB := &database.OwnerDB{DB: db}, &database.VisitorsDB{DB: db}
+	return &service{
+		dbSelector: dbselector.NewDBService(ownerDB, visitorsDB),
+		OwnerDB:    ownerDB,
+		VisitorsDB: visitorsDB,
+	}
+}
+
+func expectAbout(mock sqlmock.Sqlmock, aboutId uuid.UUID, imgUrl string) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "about" ORDER BY created_at ASC,"about"."id" LIMIT $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "nim", "major", "faculty", "biography", "slogan", "img_url"}).
+			AddRow(aboutId, "Owner", "12345", "Informatics", "Engineering", "Old bio", "Old slogan", imgUrl))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skills" WHERE "skills"."about_id" = $1`)).
+		WithArgs(aboutId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "about_id", "name"}))
+}
+
+func writeAboutPhoto(t *testing.T, url string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(url[1:]), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(url[1:], []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func aboutPhotos(t *testing.T) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(aboutUploadDir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// only the fields that are sent change, the old photo goes once the new one is committed
+func TestUpdateAbout(t *testing.T) {
+	t.Chdir(t.TempDir())
+	db, mock := testutil.NewMockDB(t)
+	s := newOwnerService(db)
+
+	aboutId := uuid.New()
+	oldPhoto := "/" + aboutUploadDir + "/old.png"
+	writeAboutPhoto(t, oldPhoto)
+
+	mock.ExpectBegin()
+	expectAbout(mock, aboutId, oldPhoto)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "about" SET "name"=$1,"nim"=$2,"major"=$3,"faculty"=$4,"biography"=$5,"slogan"=$6,"img_url"=$7`)).
+		WithArgs("New Name", "12345", "Informatics", "Engineering", "Old bio", "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), aboutId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skill_categories" ORDER BY position ASC, name ASC`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectCommit()
+
+	name, slogan := "  New Name ", ""
+	res, err := s.UpdateAbout(ownerContext(uuid.New()), UpdateAboutReq{
+		Name:   &name,
+		Slogan: &slogan,
+		Photo:  testutil.FormFile(t, "me.png", pngOf(t, 4, 4)),
+	})
+	if err != nil {
+		t.Fatalf("UpdateAbout() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Name != "New Name" || res.Biography != "Old bio" || res.Slogan != "" {
+		t.Errorf("unexpected about %+v", res)
+	}
+	if photos := aboutPhotos(t); len(photos) != 1 || "/"+filepath.ToSlash(photos[0]) != res.ImgUrl {
+		t.Errorf("photos = %v, want only the new %s", photos, res.ImgUrl)
+	}
+}
+
+func TestUpdateAboutRollbackKeepsPhoto(t *testing.T) {
+	t.Chdir(t.TempDir())
+	db, mock := testutil.NewMockDB(t)
+	s := newOwnerService(db)
+
+	oldPhoto := "/" + aboutUploadDir + "/old.png"
+	writeAboutPhoto(t, oldPhoto)
+
+	mock.ExpectBegin()
+	expectAbout(mock, uuid.New(), oldPhoto)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "about" SET`)).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	if _, err := s.UpdateAbout(ownerContext(uuid.New()), UpdateAboutReq{Photo: testutil.FormFile(t, "me.png", pngOf(t, 4, 4))}); err == nil {
+		t.Fatal("UpdateAbout() succeeded")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if photos := aboutPhotos(t); len(photos) != 1 || "/"+filepath.ToSlash(photos[0]) != oldPhoto {
+		t.Errorf("photos = %v, want only the old one", photos)
+	}
+}
+
+func TestUpdateAboutRefused(t *testing.T) {
+	blank := "   "
+
+	t.Run("blank name", func(t *testing.T) {
+		db, mock := testutil.NewMockDB(t)
+		_, err := newOwnerService(db).UpdateAbout(ownerContext(uuid.New()), UpdateAboutReq{Name: &blank})
+		if got := testutil.StatusOf(err); got != http.StatusBadRequest {
+			t.Fatalf("UpdateAbout() status = %d (%v), want 400", got, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("not set up", func(t *testing.T) {
+		db, mock := testutil.NewMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "about"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := newOwnerService(db).UpdateAbout(ownerContext(uuid.New()), UpdateAboutReq{})
+		if got := testutil.StatusOf(err); got != http.StatusNotFound {
+			t.Fatalf("UpdateAbout() status = %d (%v), want 404", got, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	contextUtil "github.com/devanadindra/portfolio/back-end/utils/context"
+	fileutils "github.com/devanadindra/portfolio/back-end/utils/file"
+	"github.com/devanadindra/portfolio/back-end/utils/thumbnail"
+)
+
+const avatarUploadDir = "uploads/avatars"
+
+// AddAvatar replaces the owner's avatar with the uploaded image, stored as a square in every
+// configured size. The content decides the format, not the filename. The previous set of
+// files is only removed once the new one is committed.
+func (s *service) AddAvatar(ctx context.Context, req AvatarReq) (*AvatarRes, error) {
+	token, err := contextUtil.GetTokenClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := s.dbSelector.GetDBByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readAvatarFile(req.Avatar, s.avatarConfig.MaxSize)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	format := thumbnail.SniffImage(data)
+	if format == "" {
+		return nil, apierror.InvalidAvatarFile(req.Avatar.Filename)
+	}
+
+	img, err := thumbnail.DecodeImage(data, s.avatarConfig.MaxPixels)
+	if errors.Is(err, thumbnail.ErrImageTooLarge) {
+		return nil, apierror.AvatarDimensionsTooLarge(s.avatarConfig.MaxPixels)
+	}
+	if err != nil {
+		return nil, apierror.InvalidAvatarFile(req.Avatar.Filename)
+	}
+
+	ownerID := token.Claims.UserID
+	avatars, err := saveAvatarVariants(ownerID, img, format, s.avatarSizes())
+	if err != nil {
+		removeMediaFiles(ctx, avatarUrls(avatars)...)
+		return nil, apierror.FromErr(err)
+	}
+
+	var oldUrls []string
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var owner Owner
+		if err := tx.Where("id = ?", ownerID).First(&owner).Error; err != nil {
+			return err
+		}
+
+		var old []OwnerAvatar
+		if err := tx.Where("owner_id = ?", ownerID).Find(&old).Error; err != nil {
+			return err
+		}
+		oldUrls = append(avatarUrls(old), owner.AvatarUrl)
+
+		if err := tx.Where("owner_id = ?", ownerID).Delete(&OwnerAvatar{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&avatars).Error; err != nil {
+			return err
+		}
+
+		// avatar_url keeps pointing at a single image, the largest one
+		return tx.Model(&owner).Update("avatar_url", avatars[len(avatars)-1].Url).Error
+	})
+	if err != nil {
+		removeMediaFiles(ctx, avatarUrls(avatars)...)
+		return nil, apierror.FromErr(err)
+	}
+
+	removeMediaFiles(ctx, oldUrls...)
+
+	res := &AvatarRes{
+		AvatarUrl: avatars[len(avatars)-1].Url,
+		Variants:  make([]AvatarVariantRes, len(avatars)),
+	}
+	for i, avatar := range avatars {
+		res.Variants[i] = AvatarVariantRes{Size: avatar.Size, Url: avatar.Url}
+	}
+
+	return res, nil
+}
+
+// avatarSizes returns the configured sizes from small to large without duplicates.
+func (s *service) avatarSizes() []int {
+	sizes := make([]int, 0, len(s.avatarConfig.Sizes))
+	for _, size := range s.avatarConfig.Sizes {
+		if size > 0 {
+			sizes = append(sizes, size)
+		}
+	}
+	slices.Sort(sizes)
+	return slices.Compact(sizes)
+}
+
+func readAvatarFile(file *multipart.FileHeader, maxSize int64) ([]byte, error) {
+	if file.Size > maxSize {
+		return nil, apierror.AvatarTooLarge(maxSize)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, apierror.AvatarTooLarge(maxSize)
+	}
+
+	return data, nil
+}
+
+// saveAvatarVariants writes one file per size. Photos stay jpeg, everything else becomes png
+// so transparency survives. The variants written so far are returned even on error so the
+// caller can clean them up.
+func saveAvatarVariants(ownerID uuid.UUID, img image.Image, format string, sizes []int) ([]OwnerAvatar, error) {
+	if len(sizes) == 0 {
+		return nil, errors.New("no avatar sizes configured")
+	}
+
+	if err := os.MkdirAll(avatarUploadDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	name, err := fileutils.GenerateMediaName(ownerID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	ext := ".png"
+	if format == "jpeg" {
+		ext = ".jpg"
+	}
+
+	avatars := make([]OwnerAvatar, 0, len(sizes))
+	for _, size := range sizes {
+		filename := fmt.Sprintf("%s_%d%s", name, size, ext)
+		avatars = append(avatars, OwnerAvatar{
+			OwnerID: ownerID,
+			Size:    size,
+			Url:     "/" + avatarUploadDir + "/" + filename,
+		})
+
+		if err := writeAvatar(filepath.Join(avatarUploadDir, filename), thumbnail.Square(img, size), ext); err != nil {
+			return avatars, err
+		}
+	}
+
+	return avatars, nil
+}
+
+func writeAvatar(path string, img image.Image, ext string) error {
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if ext == ".jpg" {
+		err = jpeg.Encode(dst, img, &jpeg.Options{Quality: 90})
+	} else {
+		err = png.Encode(dst, img)
+	}
+	if err != nil {
+		dst.Close()
+		return err
+	}
+
+	return dst.Close()
+}
+
+func avatarUrls(avatars []OwnerAvatar) []string {
+	urls := make([]string, len(avatars))
+	for i, avatar := range avatars {
+		urls[i] = avatar.Url
+	}
+	return urls
+}
@@ -0,0 +1,125 @@
+package user
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+func pngOf(t *testing.T, width int, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestAvatarSizes(t *testing.T) {
+	s := &service{avatarConfig: config.Avatar{Sizes: []int{512, 64, 0, 256, 64, -1}}}
+	if got := s.avatarSizes(); !slices.Equal(got, []int{64, 256, 512}) {
+		t.Errorf("avatarSizes() = %v", got)
+	}
+}
+
+func TestAddAvatarRefused(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+		status   int
+	}{
+		{name: "not an image", filename: "avatar.png", data: []byte("just some text, not a png"), status: http.StatusUnprocessableEntity},
+		{name: "bmp renamed", filename: "avatar.png", data: []byte("BM\x00\x00\x00\x00\x00\x00"), status: http.StatusUnprocessableEntity},
+		{name: "too many bytes", filename: "avatar.png", data: bytes.Repeat([]byte{0}, 2048), status: http.StatusRequestEntityTooLarge},
+		{name: "too many pixels", filename: "avatar.png", data: pngOf(t, 40, 30), status: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := testutil.NewMockDB(t)
+			s := newOwnerService(db)
+			s.avatarConfig = config.Avatar{MaxSize: 1024, MaxPixels: 1000, Sizes: []int{8}}
+
+			_, err := s.AddAvatar(ownerContext(uuid.New()), AvatarReq{Avatar: testutil.FormFile(t, tt.filename, tt.data)})
+			if got := testutil.StatusOf(err); got != tt.status {
+				t.Fatalf("AddAvatar() status = %d (%v), want %d", got, err, tt.status)
+			}
+			// nothing reaches the database
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestAddAvatar(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	db, mock := testutil.NewMockDB(t)
+	s := newOwnerService(db)
+	s.avatarConfig = config.Avatar{MaxSize: 1 << 20, MaxPixels: 1 << 20, Sizes: []int{32, 8}}
+
+	if err := os.MkdirAll(avatarUploadDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	oldUrl := "/" + avatarUploadDir + "/old.png"
+	if err := os.WriteFile(oldUrl[1:], []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ownerId := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "owner" WHERE id = $1`)).
+		WithArgs(ownerId, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "avatar_url"}).AddRow(ownerId, ""))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "owner_avatars" WHERE owner_id = $1`)).
+		WithArgs(ownerId).
+		WillReturnRows(sqlmock.NewRows([]string{"owner_id", "size", "url"}).AddRow(ownerId, 64, oldUrl))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "owner_avatars" WHERE owner_id = $1`)).
+		WithArgs(ownerId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "owner_avatars"`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "owner" SET "avatar_url"=$1`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// the content is a png, the name does not matter
+	res, err := s.AddAvatar(ownerContext(ownerId), AvatarReq{Avatar: testutil.FormFile(t, "avatar.jpg", pngOf(t, 60, 40))})
+	if err != nil {
+		t.Fatalf("AddAvatar() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Variants) != 2 || res.Variants[0].Size != 8 || res.Variants[1].Size != 32 || res.AvatarUrl != res.Variants[1].Url {
+		t.Fatalf("unexpected response %+v", res)
+	}
+	for _, variant := range res.Variants {
+		data, err := os.ReadFile(variant.Url[1:])
+		if err != nil {
+			t.Fatalf("variant %d not written: %v", variant.Size, err)
+		}
+		conf, err := png.DecodeConfig(bytes.NewReader(data))
+		if err != nil || conf.Width != variant.Size || conf.Height != variant.Size {
+			t.Errorf("variant %d is %dx%d (%v)", variant.Size, conf.Width, conf.Height, err)
+		}
+	}
+	if _, err := os.Stat(oldUrl[1:]); !os.IsNotExist(err) {
+		t.Error("the previous avatar was not removed")
+	}
+}
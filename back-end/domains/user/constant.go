@@ -5,3 +5,9 @@ const (
 	ErrEmailNotFound      = "email not found"
 	ErrInvalidCurPassword = "Current Password Invalid"
 )
+
+const (
+	// the refresh cookie is named after the access cookie, e.g. refresh_token_admin
+	REFRESH_COOKIE_PREFIX = "refresh_"
+	REFRESH_COOKIE_PATH   = "/api/user"
+)
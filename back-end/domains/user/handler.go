@@ -9,6 +9,7 @@ import (
 	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
 	"github.com/devanadindra/portfolio/back-end/utils/config"
 	contextUtil "github.com/devanadindra/portfolio/back-end/utils/context"
+	fileutils "github.com/devanadindra/portfolio/back-end/utils/file"
 	"github.com/devanadindra/portfolio/back-end/utils/respond"
 )
 
@@ -21,17 +22,27 @@ type Handler interface {
 	ResetPassword(ctx *gin.Context)
 	ResetPasswordSubmit(ctx *gin.Context)
 	GetAbout(ctx *gin.Context)
+	UpdateAbout(ctx *gin.Context)
+	LoginTwoFactor(ctx *gin.Context)
+	Refresh(ctx *gin.Context)
+	GetTwoFactorStatus(ctx *gin.Context)
+	EnrollTwoFactor(ctx *gin.Context)
+	ConfirmTwoFactor(ctx *gin.Context)
+	DisableTwoFactor(ctx *gin.Context)
+	RegenerateRecoveryCodes(ctx *gin.Context)
 }
 
 type handler struct {
-	service  Service
-	validate *validator.Validate
+	service     Service
+	validate    *validator.Validate
+	environment config.Environment
 }
 
-func NewHandler(service Service, validate *validator.Validate) Handler {
+func NewHandler(config *config.Config, service Service, validate *validator.Validate) Handler {
 	return &handler{
-		service:  service,
-		validate: validate,
+		service:     service,
+		validate:    validate,
+		environment: config.Environment,
 	}
 }
 
@@ -54,111 +65,153 @@ func (h *handler) Login(ctx *gin.Context) {
 		return
 	}
 
-	frontend := ctx.GetHeader("X-Frontend")
-	env := config.DEVELOPMENT_ENVIRONMENT
-	cookieName := ""
-	if frontend == "admin" {
-		cookieName = "token_admin"
-	} else {
-		cookieName = "token_user"
+	// with two factor on the cookie is only set by the second login step
+	if !res.TwoFactorRequired {
+		h.setAuthCookies(ctx, res)
 	}
 
-	cookieDomain := ""
-	cookieDomainAdmin := ""
-	secure := false
-	sameSite := http.SameSiteLaxMode
+	respond.Success(ctx, http.StatusOK, res)
+}
 
-	if env == config.PRODUCTION_ENVIRONMENT {
-		cookieDomain = ""
-		cookieDomainAdmin = ""
-		secure = true
-		sameSite = http.SameSiteNoneMode
-	}
+func (h *handler) VerifyToken(ctx *gin.Context) {
+	respond.Success(ctx, http.StatusOK, VerifyTokenRes{TokenVerified: true})
+}
 
-	http.SetCookie(ctx.Writer, &http.Cookie{
-		Name:     cookieName,
-		Value:    res.Token,
-		Expires:  res.Expires,
-		Path:     "/",
-		HttpOnly: true,
-		Domain:   cookieDomain,
-		Secure:   secure,
-		SameSite: sameSite,
-	})
+func (h *handler) Logout(ctx *gin.Context) {
+	h.setAuthCookies(ctx, nil)
+
+	// an expired access token must not keep the session alive, the refresh token is
+	// revoked either way
+	input := LogoutReq{RefreshToken: refreshCookie(ctx)}
+	if token, err := contextUtil.GetTokenClaims(ctx); err == nil {
+		input.Token = token.Token
+		input.Expires = token.Claims.ExpiresAt.Time
+	}
 
-	http.SetCookie(ctx.Writer, &http.Cookie{
-		Name:     cookieName,
-		Value:    res.Token,
-		Expires:  res.Expires,
-		Path:     "/",
-		HttpOnly: true,
-		Domain:   cookieDomainAdmin,
-		Secure:   secure,
-		SameSite: sameSite,
-	})
+	res, err := h.service.Logout(ctx, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
 
 	respond.Success(ctx, http.StatusOK, res)
 }
 
-func (h *handler) VerifyToken(ctx *gin.Context) {
-	respond.Success(ctx, http.StatusOK, VerifyTokenRes{TokenVerified: true})
+func (h *handler) ChangePassword(ctx *gin.Context) {
+	var input ChangePasswordReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	input.RefreshToken = refreshCookie(ctx)
+
+	if err := h.service.ChangePassword(ctx, input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "Password changed successfully, other sessions have been signed out"})
 }
 
-func (h *handler) Logout(ctx *gin.Context) {
-	frontend := ctx.GetHeader("X-Frontend")
-	env := config.DEVELOPMENT_ENVIRONMENT
-	cookieName := ""
-	if frontend == "admin" {
-		cookieName = "token_admin"
-	} else {
-		cookieName = "token_user"
+func (h *handler) AddAvatar(ctx *gin.Context) {
+	var input AvatarReq
+	if err := ctx.ShouldBind(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
 	}
 
-	cookieDomain := ""
-	cookieDomainAdmin := ""
-	secure := false
-	sameSite := http.SameSiteLaxMode
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
 
-	if env == config.PRODUCTION_ENVIRONMENT {
-		cookieDomain = ""
-		cookieDomainAdmin = ""
-		secure = true
-		sameSite = http.SameSiteNoneMode
+	res, err := h.service.AddAvatar(ctx, input)
+	if err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
 	}
-	http.SetCookie(ctx.Writer, &http.Cookie{
-		Name:     cookieName,
-		Value:    "",
-		Path:     "/",
-		MaxAge:   -1,
-		HttpOnly: true,
-		Domain:   cookieDomain,
-		Secure:   secure,
-		SameSite: sameSite,
-	})
 
-	http.SetCookie(ctx.Writer, &http.Cookie{
-		Name:     cookieName,
-		Value:    "",
-		Path:     "/",
-		MaxAge:   -1,
-		HttpOnly: true,
-		Domain:   cookieDomainAdmin,
-		Secure:   secure,
-		SameSite: sameSite,
-	})
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) ResetPassword(ctx *gin.Context) {
+	var input ResetPasswordReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
 
-	token, err := contextUtil.GetTokenClaims(ctx)
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	if err := h.service.ResetPassword(ctx, input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	// the same answer whether or not the email belongs to an account
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "If the email is registered, a reset link has been sent to it"})
+}
+
+func (h *handler) ResetPasswordSubmit(ctx *gin.Context) {
+	var input ResetPasswordSubmitReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+	if err := h.service.ResetPasswordSubmit(ctx, input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
+func (h *handler) GetAbout(ctx *gin.Context) {
+	res, err := h.service.GetAbout(ctx)
 	if err != nil {
 		respond.Error(ctx, apierror.FromErr(err))
 		return
 	}
 
-	input := LogoutReq{
-		Token:   token.Token,
-		Expires: token.Claims.ExpiresAt.Time,
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) UpdateAbout(ctx *gin.Context) {
+	var input UpdateAboutReq
+	if err := ctx.ShouldBind(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
 	}
 
-	res, err := h.service.Logout(ctx, input)
+	// the length limits apply to the stored value
+	input.Name = trimmed(input.Name)
+	input.Nim = trimmed(input.Nim)
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	if input.Photo != nil && !fileutils.IsValidImage(input.Photo) {
+		respond.Error(ctx, apierror.InvalidImageFile(input.Photo.Filename))
+		return
+	}
+
+	res, err := h.service.UpdateAbout(ctx, input)
 	if err != nil {
 		respond.Error(ctx, apierror.FromErr(err))
 		return
@@ -167,8 +220,25 @@ func (h *handler) Logout(ctx *gin.Context) {
 	respond.Success(ctx, http.StatusOK, res)
 }
 
-func (h *handler) ChangePassword(ctx *gin.Context) {
-	var input ChangePasswordReq
+func (h *handler) Refresh(ctx *gin.Context) {
+	res, err := h.service.Refresh(ctx, RefreshReq{RefreshToken: refreshCookie(ctx)})
+	if err != nil {
+		// a failing database must not sign the owner out, only a refresh token that is no
+		// good anymore does
+		if apierror.GetApiErrors(err).Code == http.StatusUnauthorized {
+			h.setAuthCookies(ctx, nil)
+		}
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	h.setAuthCookies(ctx, res)
+
+	respond.Success(ctx, http.StatusOK, res)
+}
+
+func (h *handler) LoginTwoFactor(ctx *gin.Context) {
+	var input LoginTwoFactorReq
 	if err := ctx.ShouldBindJSON(&input); err != nil {
 		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
 		return
@@ -179,39 +249,39 @@ func (h *handler) ChangePassword(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.ChangePassword(ctx, input); err != nil {
+	res, err := h.service.LoginTwoFactor(ctx, input)
+	if err != nil {
 		respond.Error(ctx, apierror.FromErr(err))
 		return
 	}
 
-	respond.Success(ctx, http.StatusOK, gin.H{"message": "Password changed successfully"})
+	h.setAuthCookies(ctx, res)
+
+	respond.Success(ctx, http.StatusOK, res)
 }
 
-func (h *handler) AddAvatar(ctx *gin.Context) {
-	file, err := ctx.FormFile("avatar")
+func (h *handler) GetTwoFactorStatus(ctx *gin.Context) {
+	res, err := h.service.GetTwoFactorStatus(ctx)
 	if err != nil {
 		respond.Error(ctx, apierror.FromErr(err))
 		return
 	}
 
-	req := AvatarReq{
-		AvatarUrl: file,
-	}
+	respond.Success(ctx, http.StatusOK, res)
+}
 
-	newUrl, err := h.service.AddAvatar(ctx.Request.Context(), req)
+func (h *handler) EnrollTwoFactor(ctx *gin.Context) {
+	res, err := h.service.EnrollTwoFactor(ctx)
 	if err != nil {
 		respond.Error(ctx, apierror.FromErr(err))
 		return
 	}
 
-	respond.Success(ctx, http.StatusOK, gin.H{
-		"message":   "Avatar added successfully",
-		"avatarUrl": newUrl,
-	})
+	respond.Success(ctx, http.StatusOK, res)
 }
 
-func (h *handler) ResetPassword(ctx *gin.Context) {
-	var input ResetPasswordReq
+func (h *handler) ConfirmTwoFactor(ctx *gin.Context) {
+	var input ConfirmTwoFactorReq
 	if err := ctx.ShouldBindJSON(&input); err != nil {
 		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
 		return
@@ -222,7 +292,9 @@ func (h *handler) ResetPassword(ctx *gin.Context) {
 		return
 	}
 
-	res, err := h.service.ResetPassword(ctx, input)
+	input.RefreshToken = refreshCookie(ctx)
+
+	res, err := h.service.ConfirmTwoFactor(ctx, input)
 	if err != nil {
 		respond.Error(ctx, apierror.FromErr(err))
 		return
@@ -231,8 +303,8 @@ func (h *handler) ResetPassword(ctx *gin.Context) {
 	respond.Success(ctx, http.StatusOK, res)
 }
 
-func (h *handler) ResetPasswordSubmit(ctx *gin.Context) {
-	var input ResetPasswordSubmitReq
+func (h *handler) DisableTwoFactor(ctx *gin.Context) {
+	var input TwoFactorPasswordReq
 	if err := ctx.ShouldBindJSON(&input); err != nil {
 		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
 		return
@@ -242,16 +314,30 @@ func (h *handler) ResetPasswordSubmit(ctx *gin.Context) {
 		respond.Error(ctx, apierror.FromErr(err))
 		return
 	}
-	if err := h.service.ResetPasswordSubmit(ctx, input); err != nil {
+
+	input.RefreshToken = refreshCookie(ctx)
+
+	if err := h.service.DisableTwoFactor(ctx, input); err != nil {
 		respond.Error(ctx, apierror.FromErr(err))
 		return
 	}
 
-	respond.Success(ctx, http.StatusOK, gin.H{"message": "Password reset successfully"})
+	respond.Success(ctx, http.StatusOK, gin.H{"message": "Two factor authentication disabled, other sessions have been signed out"})
 }
 
-func (h *handler) GetAbout(ctx *gin.Context) {
-	res, err := h.service.GetAbout(ctx)
+func (h *handler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var input TwoFactorPasswordReq
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		respond.Error(ctx, apierror.Warn(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		respond.Error(ctx, apierror.FromErr(err))
+		return
+	}
+
+	res, err := h.service.RegenerateRecoveryCodes(ctx, input)
 	if err != nil {
 		respond.Error(ctx, apierror.FromErr(err))
 		return
@@ -259,3 +345,66 @@ func (h *handler) GetAbout(ctx *gin.Context) {
 
 	respond.Success(ctx, http.StatusOK, res)
 }
+
+// setAuthCookies sets the cookies of the calling front-end, the admin reads token_admin and
+// the public site token_user. The refresh token cookie is only sent to the user routes that
+// need it. Without a res both cookies are removed.
+func (h *handler) setAuthCookies(ctx *gin.Context, res *LoginRes) {
+	frontend := ctx.GetHeader("X-Frontend")
+	cookieName := ""
+	if frontend == "admin" {
+		cookieName = "token_admin"
+	} else {
+		cookieName = "token_user"
+	}
+
+	cookieDomain := ""
+	secure := false
+	sameSite := http.SameSiteLaxMode
+
+	if h.environment == config.PRODUCTION_ENVIRONMENT {
+		cookieDomain = ""
+		secure = true
+		sameSite = http.SameSiteNoneMode
+	}
+
+	access := &http.Cookie{
+		Name:     cookieName,
+		Path:     "/",
+		HttpOnly: true,
+		Domain:   cookieDomain,
+		Secure:   secure,
+		SameSite: sameSite,
+	}
+	refresh := *access
+	refresh.Name = REFRESH_COOKIE_PREFIX + cookieName
+	refresh.Path = REFRESH_COOKIE_PATH
+
+	if res == nil {
+		access.MaxAge, refresh.MaxAge = -1, -1
+	} else {
+		access.Value, access.Expires = res.Token, res.Expires
+		if res.RefreshExpires != nil {
+			refresh.Value, refresh.Expires = res.RefreshToken, *res.RefreshExpires
+		}
+	}
+
+	http.SetCookie(ctx.Writer, access)
+	if res == nil || res.RefreshToken != "" {
+		http.SetCookie(ctx.Writer, &refresh)
+	}
+}
+
+// refreshCookie returns the refresh token cookie of the calling front-end, if any.
+func refreshCookie(ctx *gin.Context) string {
+	cookieName := "token_user"
+	if ctx.GetHeader("X-Frontend") == "admin" {
+		cookieName = "token_admin"
+	}
+
+	cookie, err := ctx.Request.Cookie(REFRESH_COOKIE_PREFIX + cookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
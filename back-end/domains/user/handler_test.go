@@ -0,0 +1,206 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+)
+
+// stubService records the input of the calls a test makes, every other method panics.
+type stubService struct {
+	Service
+	about      *UpdateAboutReq
+	refresh    *RefreshReq
+	refreshRes *LoginRes
+	refreshErr error
+}
+
+func (s *stubService) UpdateAbout(ctx context.Context, input UpdateAboutReq) (*AboutRes, error) {
+	s.about = &input
+	return &AboutRes{}, nil
+}
+
+func (s *stubService) Refresh(ctx context.Context, input RefreshReq) (*LoginRes, error) {
+	s.refresh = &input
+	return s.refreshRes, s.refreshErr
+}
+
+func postForm(t *testing.T, handle gin.HandlerFunc, fields map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for name, value := range fields {
+		w.WriteField(name, value)
+	}
+	w.Close()
+
+	rec := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rec)
+	ctx.Request = httptest.NewRequest(http.MethodPatch, "/", &body)
+	ctx.Request.Header.Set("Content-Type", w.FormDataContentType())
+	handle(ctx)
+	return rec
+}
+
+func TestUpdateAboutTrimsBeforeValidating(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		fields  map[string]string
+		status  int
+		wantNim *string
+	}{
+		{name: "nim not sent", fields: map[string]string{"major": "CS"}, status: http.StatusOK},
+		{name: "nim padded to its limit", fields: map[string]string{"nim": "  12345678901  "}, status: http.StatusOK, wantNim: common.ValueToPointer("12345678901")},
+		{name: "nim too long", fields: map[string]string{"nim": "123456789012"}, status: http.StatusBadRequest},
+		{name: "nim empty", fields: map[string]string{"nim": ""}, status: http.StatusBadRequest},
+		{name: "nim blank", fields: map[string]string{"nim": "   "}, status: http.StatusBadRequest},
+		{name: "name blank", fields: map[string]string{"name": " \t "}, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stubService{}
+			h := NewHandler(&config.Config{}, service, validator.New())
+
+			rec := postForm(t, h.UpdateAbout, tt.fields)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status != http.StatusOK {
+				if service.about != nil {
+					t.Error("the service was called with an invalid request")
+				}
+				return
+			}
+
+			got := service.about.Nim
+			if (got == nil) != (tt.wantNim == nil) || (got != nil && *got != *tt.wantNim) {
+				t.Errorf("nim = %v, want %v", got, tt.wantNim)
+			}
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	expires := time.Now().Add(time.Hour)
+	tests := []struct {
+		name        string
+		res         *LoginRes
+		err         error
+		status      int
+		wantCookies map[string]string
+	}{
+		{
+			name:        "rotated",
+			res:         &LoginRes{Token: "access", Expires: expires, RefreshToken: "next", RefreshExpires: &expires},
+			status:      http.StatusOK,
+			wantCookies: map[string]string{"token_admin": "access", "refresh_token_admin": "next"},
+		},
+		{
+			name:        "refresh token no good",
+			err:         apierror.InvalidRefreshToken(),
+			status:      http.StatusUnauthorized,
+			wantCookies: map[string]string{"token_admin": "", "refresh_token_admin": ""},
+		},
+		{
+			name:        "server error keeps the cookies",
+			err:         errors.New("connection refused"),
+			status:      http.StatusInternalServerError,
+			wantCookies: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stubService{refreshRes: tt.res, refreshErr: tt.err}
+			h := NewHandler(&config.Config{}, service, validator.New())
+
+			rec := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(rec)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+			ctx.Request.Header.Set("X-Frontend", "admin")
+			ctx.Request.AddCookie(&http.Cookie{Name: "refresh_token_admin", Value: "current"})
+			h.Refresh(ctx)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if service.refresh == nil || service.refresh.RefreshToken != "current" {
+				t.Errorf("service got %+v, want the refresh cookie", service.refresh)
+			}
+
+			cookies := map[string]string{}
+			for _, cookie := range rec.Result().Cookies() {
+				cookies[cookie.Name] = cookie.Value
+			}
+			if len(cookies) != len(tt.wantCookies) {
+				t.Fatalf("cookies = %v, want %v", cookies, tt.wantCookies)
+			}
+			for name, value := range tt.wantCookies {
+				if got, ok := cookies[name]; !ok || got != value {
+					t.Errorf("cookie %s = %q, want %q", name, got, value)
+				}
+			}
+
+			if strings.Contains(rec.Body.String(), "next") {
+				t.Errorf("the refresh token is in the body: %s", rec.Body)
+			}
+		})
+	}
+}
+
+// production cookies are only sent over https and to the front-ends on other origins
+func TestSetAuthCookiesEnvironment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	expires := time.Now().Add(time.Hour)
+	res := &LoginRes{Token: "access", Expires: expires, RefreshToken: "next", RefreshExpires: &expires}
+
+	tests := []struct {
+		environment config.Environment
+		secure      bool
+		sameSite    http.SameSite
+	}{
+		{environment: config.DEVELOPMENT_ENVIRONMENT, sameSite: http.SameSiteLaxMode},
+		{environment: config.PRODUCTION_ENVIRONMENT, secure: true, sameSite: http.SameSiteNoneMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.environment), func(t *testing.T) {
+			h := NewHandler(&config.Config{Environment: tt.environment}, &stubService{}, validator.New()).(*handler)
+
+			rec := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(rec)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+			h.setAuthCookies(ctx, res)
+
+			cookies := rec.Result().Cookies()
+			if len(cookies) != 2 {
+				t.Fatalf("cookies = %v, want the access and refresh cookie", cookies)
+			}
+			for _, cookie := range cookies {
+				if cookie.Secure != tt.secure || cookie.SameSite != tt.sameSite {
+					t.Errorf("cookie %s secure = %v, same site = %v, want %v, %v", cookie.Name, cookie.Secure, cookie.SameSite, tt.secure, tt.sameSite)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	"github.com/devanadindra/portfolio/back-end/utils/logger"
+)
+
+// Refresh exchanges a refresh token for a new access token and the next refresh token of
+// its family. A refresh token is only good once: presenting one that was already exchanged
+// means it leaked, so every token of the family is revoked and the owner has to sign in again.
+func (s *service) Refresh(ctx context.Context, input RefreshReq) (*LoginRes, error) {
+	if input.RefreshToken == "" {
+		return nil, apierror.InvalidRefreshToken()
+	}
+
+	var res *LoginRes
+	var reuseErr error
+	err := s.OwnerDB.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var token RefreshToken
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", hashToken(input.RefreshToken)).
+			First(&token).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apierror.InvalidRefreshToken()
+		}
+		if err != nil {
+			return err
+		}
+
+		if token.RevokedAt != nil || !token.ExpiresAt.After(now) {
+			return apierror.InvalidRefreshToken()
+		}
+
+		if token.UsedAt != nil {
+			// the revocation is committed, only the response is an error
+			logger.Warn(ctx, "refresh token reused, revoking family %s of owner %s", token.FamilyID, token.OwnerID)
+			reuseErr = apierror.InvalidRefreshToken()
+			return revokeFamily(tx, token.FamilyID, now)
+		}
+
+		if err := tx.Model(&token).Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		var owner Owner
+		if err := tx.Where("id = ?", token.OwnerID).First(&owner).Error; err != nil {
+			return err
+		}
+
+		res, err = s.issueToken(tx, owner, constants.OWNER, token.FamilyID)
+		return err
+	})
+	if err == nil {
+		err = reuseErr
+	}
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return res, nil
+}
+
+// revokeFamilyOf ends the session the refresh token belongs to, unknown tokens are ignored.
+func (s *service) revokeFamilyOf(db *gorm.DB, refreshToken string) error {
+	var token RefreshToken
+	err := db.Select("family_id").Where("token_hash = ?", hashToken(refreshToken)).First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return revokeFamily(db, token.FamilyID, time.Now())
+}
+
+func revokeFamily(tx *gorm.DB, familyID uuid.UUID, now time.Time) error {
+	return tx.Model(&RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error
+}
+
+// revokeOwnerSessions revokes every refresh token of the owner and drops the expired ones.
+// The session of keepRefreshToken, when given, is left alone so the owner stays signed in
+// where the change was made. Access tokens already handed out stay valid until they expire,
+// which is kept short.
+func revokeOwnerSessions(tx *gorm.DB, ownerID uuid.UUID, keepRefreshToken string) error {
+	now := time.Now()
+
+	if err := tx.Where("owner_id = ? AND expires_at < ?", ownerID, now).
+		Delete(&RefreshToken{}).Error; err != nil {
+		return err
+	}
+
+	query := tx.Model(&RefreshToken{}).Where("owner_id = ? AND revoked_at IS NULL", ownerID)
+	if keepRefreshToken != "" {
+		query = query.Where("family_id NOT IN (?)", tx.Model(&RefreshToken{}).
+			Select("family_id").
+			Where("token_hash = ? AND owner_id = ?", hashToken(keepRefreshToken), ownerID))
+	}
+	return query.Update("revoked_at", now).Error
+}
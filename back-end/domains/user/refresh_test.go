@@ -0,0 +1,172 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+func newRefreshService(db *gorm.DB) *service {
+	return &service{
+		authConfig: config.Auth{JWT: config.JWT{ExpireIn: 15 * time.Minute, RefreshExpireIn: 24 * time.Hour, SecretKey: "secret"}},
+		OwnerDB:    &database.OwnerDB{DB: db},
+	}
+}
+
+func refreshTokenRows(id, ownerId, familyId uuid.UUID, expiresAt time.Time, usedAt, revokedAt *time.Time) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "owner_id", "family_id", "token_hash", "expires_at", "used_at", "revoked_at"}).
+		AddRow(id, ownerId, familyId, hashToken("presented"), expiresAt, usedAt, revokedAt)
+}
+
+func expectRefreshToken(mock sqlmock.Sqlmock, rows *sqlmock.Rows) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "refresh_tokens" WHERE token_hash = $1 ORDER BY "refresh_tokens"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(hashToken("presented"), 1).
+		WillReturnRows(rows)
+}
+
+func expectRevokeOwnerSessions(mock sqlmock.Sqlmock, ownerId uuid.UUID, keepRefreshToken string) {
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "refresh_tokens" WHERE owner_id = $1 AND expires_at < $2`)).
+		WithArgs(ownerId, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	if keepRefreshToken == "" {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "refresh_tokens" SET "revoked_at"=$1 WHERE owner_id = $2 AND revoked_at IS NULL`)+`$`).
+			WithArgs(sqlmock.AnyArg(), ownerId).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		return
+	}
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "refresh_tokens" SET "revoked_at"=$1 WHERE (owner_id = $2 AND revoked_at IS NULL) AND family_id NOT IN (SELECT "family_id" FROM "refresh_tokens" WHERE token_hash = $3 AND owner_id = $4)`)).
+		WithArgs(sqlmock.AnyArg(), ownerId, hashToken(keepRefreshToken), ownerId).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+}
+
+func TestRevokeOwnerSessions(t *testing.T) {
+	for _, keep := range []string{"", "current"} {
+		t.Run("keep "+keep, func(t *testing.T) {
+			db, mock := testutil.NewMockDB(t)
+			ownerId := uuid.New()
+			mock.ExpectBegin()
+			expectRevokeOwnerSessions(mock, ownerId, keep)
+			mock.ExpectCommit()
+
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return revokeOwnerSessions(tx, ownerId, keep)
+			})
+			if err != nil {
+				t.Fatalf("revokeOwnerSessions() error = %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestRefreshRotates(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	s := newRefreshService(db)
+
+	tokenId, ownerId, familyId := uuid.New(), uuid.New(), uuid.New()
+	expectRefreshToken(mock, refreshTokenRows(tokenId, ownerId, familyId, time.Now().Add(time.Hour), nil, nil))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "refresh_tokens" SET "used_at"=$1 WHERE "id" = $2`)).
+		WithArgs(sqlmock.AnyArg(), tokenId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "owner" WHERE id = $1`)).
+		WithArgs(ownerId, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(ownerId, "owner@example.com"))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "refresh_tokens" WHERE owner_id = $1 AND expires_at < $2`)).
+		WithArgs(ownerId, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// the next token stays in the family of the one presented
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "refresh_tokens" ("owner_id","family_id","token_hash"`)).
+		WithArgs(ownerId, familyId, sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	res, err := s.Refresh(context.Background(), RefreshReq{RefreshToken: "presented"})
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if res.Token == "" || res.RefreshToken == "" || res.RefreshToken == "presented" || res.RefreshExpires == nil {
+		t.Errorf("unexpected response %+v", res)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// presenting a token that was already exchanged revokes its whole family
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	s := newRefreshService(db)
+
+	usedAt := time.Now().Add(-time.Minute)
+	familyId := uuid.New()
+	expectRefreshToken(mock, refreshTokenRows(uuid.New(), uuid.New(), familyId, time.Now().Add(time.Hour), &usedAt, nil))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "refresh_tokens" SET "revoked_at"=$1 WHERE family_id = $2 AND revoked_at IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), familyId).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	_, err := s.Refresh(context.Background(), RefreshReq{RefreshToken: "presented"})
+	if got := testutil.StatusOf(err); got != http.StatusUnauthorized {
+		t.Fatalf("Refresh() status = %d (%v), want 401", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefreshRefused(t *testing.T) {
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name string
+		rows *sqlmock.Rows
+	}{
+		{name: "unknown", rows: sqlmock.NewRows([]string{"id"})},
+		{name: "expired", rows: refreshTokenRows(uuid.New(), uuid.New(), uuid.New(), past, nil, nil)},
+		{name: "revoked", rows: refreshTokenRows(uuid.New(), uuid.New(), uuid.New(), future, nil, &past)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := testutil.NewMockDB(t)
+			s := newRefreshService(db)
+
+			expectRefreshToken(mock, tt.rows)
+			mock.ExpectRollback()
+
+			_, err := s.Refresh(context.Background(), RefreshReq{RefreshToken: "presented"})
+			if got := testutil.StatusOf(err); got != http.StatusUnauthorized {
+				t.Fatalf("Refresh() status = %d (%v), want 401", got, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestRefreshWithoutToken(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	s := newRefreshService(db)
+
+	_, err := s.Refresh(context.Background(), RefreshReq{})
+	if got := testutil.StatusOf(err); got != http.StatusUnauthorized {
+		t.Fatalf("Refresh() status = %d (%v), want 401", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -13,14 +13,40 @@ type LoginReq struct {
 	Role     constants.ROLE `json:"role" validate:"omitempty,oneof=OWNER CUSTOMER"`
 }
 
+// LoginTwoFactorReq finishes a login with either a totp code or a recovery code.
+type LoginTwoFactorReq struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+type ConfirmTwoFactorReq struct {
+	Code string `json:"code" validate:"required"`
+	// set from the refresh cookie, that session stays signed in
+	RefreshToken string `json:"-"`
+}
+
+type TwoFactorPasswordReq struct {
+	Password string `json:"password" validate:"required"`
+	// set from the refresh cookie by the handler, not part of the body
+	RefreshToken string `json:"-"`
+}
+
 type LogoutReq struct {
-	Token   string
-	Expires time.Time
+	Token        string
+	Expires      time.Time
+	RefreshToken string
+}
+
+// RefreshReq carries the refresh cookie, the token is never read from a body.
+type RefreshReq struct {
+	RefreshToken string
 }
 
 type ChangePasswordReq struct {
 	CurrentPassword string `json:"current_password" validate:"required"`
 	NewPassword     string `json:"new_password" validate:"required"`
+	// refresh cookie of the session making the change, every other session is ended
+	RefreshToken string `json:"-"`
 }
 
 type UpdateProfileReq struct {
@@ -29,16 +55,28 @@ type UpdateProfileReq struct {
 }
 
 type AvatarReq struct {
-	AvatarUrl *multipart.FileHeader `json:"avatar" validate:"required"`
+	Avatar *multipart.FileHeader `form:"avatar" validate:"required"`
+}
+
+// UpdateAboutReq only changes the fields that are sent, an empty value clears the optional ones.
+// Name and nim are required, they are trimmed before validation.
+type UpdateAboutReq struct {
+	Name      *string               `form:"name" validate:"omitempty,min=1,max=255"`
+	Nim       *string               `form:"nim" validate:"omitempty,min=1,max=11"`
+	Major     *string               `form:"major" validate:"omitempty,max=255"`
+	Faculty   *string               `form:"faculty" validate:"omitempty,max=255"`
+	Biography *string               `form:"biography" validate:"omitempty,max=5000"`
+	Slogan    *string               `form:"slogan" validate:"omitempty,max=1000"`
+	Photo     *multipart.FileHeader `form:"photo"`
 }
 
 type ResetPasswordReq struct {
-	Email string         `json:"email" validate:"required"`
-	Role  constants.ROLE `json:"role" validate:"omitempty,oneof=ADMIN CUSTOMER"`
+	Email string `json:"email" validate:"required,email"`
 }
 
+// ResetPasswordSubmitReq carries the token from the emailed link, it decides whose
+// password is changed.
 type ResetPasswordSubmitReq struct {
-	Email       string         `json:"email" validate:"required"`
-	NewPassword string         `json:"newPassword" validate:"required"`
-	Role        constants.ROLE `json:"role" validate:"omitempty,oneof=ADMIN CUSTOMER"`
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,min=8,max=72"`
 }
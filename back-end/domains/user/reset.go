@@ -0,0 +1,129 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/logger"
+	"github.com/devanadindra/portfolio/back-end/utils/mailer"
+)
+
+// ResetPassword emails a single use reset link to the owner with that email. The lookup and
+// the email run in the background and errors are only logged, so known and unknown emails get
+// the same response in the same time.
+func (s *service) ResetPassword(ctx context.Context, req ResetPasswordReq) error {
+	go func(ctx context.Context) {
+		if err := s.sendResetLink(ctx, req.Email); err != nil {
+			logger.Error(ctx, "%v", err)
+		}
+	}(context.WithoutCancel(ctx))
+
+	return nil
+}
+
+// sendResetLink stores a new reset token for the owner with that email and mails the link.
+// Unknown emails are not an error and nothing is sent.
+func (s *service) sendResetLink(ctx context.Context, email string) error {
+	db := s.OwnerDB.DB.WithContext(ctx)
+
+	var owner Owner
+	err := db.Where("email = ?", email).First(&owner).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// only the latest link works, older and expired ones are dropped
+		if err := tx.Where("owner_id = ? AND (used_at IS NULL OR expires_at < ?)", owner.ID, now).
+			Delete(&PasswordResetToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&PasswordResetToken{
+			OwnerID:   owner.ID,
+			TokenHash: hashToken(token),
+			ExpiresAt: now.Add(s.authConfig.PasswordReset.TokenTTL),
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      []string{owner.Email},
+		Subject: "Reset your password",
+		Body:    s.resetBody(owner, token),
+	})
+}
+
+// ResetPasswordSubmit sets the new password of the owner the token was issued to and uses
+// up the token.
+func (s *service) ResetPasswordSubmit(ctx context.Context, req ResetPasswordSubmitReq) error {
+	hashedPassword, err := hashPassword(req.NewPassword)
+	if err != nil {
+		return apierror.FromErr(err)
+	}
+
+	err = s.OwnerDB.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var token PasswordResetToken
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hashToken(req.Token), now).
+			First(&token).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apierror.InvalidResetToken()
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&Owner{}).
+			Where("id = ?", token.OwnerID).
+			Update("password", hashedPassword).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&token).Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		if err := revokeOwnerSessions(tx, token.OwnerID, ""); err != nil {
+			return err
+		}
+
+		// any other link sent before is void once the password changed
+		return tx.Where("owner_id = ? AND id <> ?", token.OwnerID, token.ID).
+			Delete(&PasswordResetToken{}).Error
+	})
+	if err != nil {
+		return apierror.FromErr(err)
+	}
+
+	return nil
+}
+
+func (s *service) resetBody(owner Owner, token string) string {
+	link := s.authConfig.PasswordReset.LinkUrl + "?" + url.Values{"token": {token}}.Encode()
+	return fmt.Sprintf("Hi %s,\n\n"+
+		"Someone asked to reset the password of your portfolio account. Open the link below to choose a new one:\n\n"+
+		"%s\n\n"+
+		"The link can be used once and expires in %s. If you did not ask for this you can ignore this email.\n",
+		owner.Name, link, s.authConfig.PasswordReset.TokenTTL)
+}
@@ -0,0 +1,119 @@
+package user
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/mailer"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+func newResetService(db *gorm.DB, sender mailer.Sender) *service {
+	return &service{
+		authConfig: config.Auth{PasswordReset: config.PasswordReset{TokenTTL: 30 * time.Minute, LinkUrl: "http://localhost/reset"}},
+		mailer:     sender,
+		OwnerDB:    &database.OwnerDB{DB: db},
+	}
+}
+
+func expectOwnerByEmail(mock sqlmock.Sqlmock, email string, ownerId uuid.UUID) *sqlmock.ExpectedQuery {
+	return mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "owner" WHERE email = $1`)).
+		WithArgs(email, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(ownerId, "Owner", email))
+}
+
+func expectResetToken(mock sqlmock.Sqlmock, ownerId uuid.UUID) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "password_reset_tokens" WHERE owner_id = $1`)).
+		WithArgs(ownerId, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "password_reset_tokens"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+}
+
+func TestSendResetLink(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	sender := mailer.NewMemorySender()
+	s := newResetService(db, sender)
+
+	ownerId := uuid.New()
+	expectOwnerByEmail(mock, "owner@example.com", ownerId)
+	expectResetToken(mock, ownerId)
+
+	if err := s.sendResetLink(context.Background(), "owner@example.com"); err != nil {
+		t.Fatalf("sendResetLink() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := sender.Messages()
+	if len(messages) != 1 || messages[0].To[0] != "owner@example.com" {
+		t.Fatalf("sent %+v, want one email to the owner", messages)
+	}
+	if !strings.Contains(messages[0].Body, "http://localhost/reset?token=") {
+		t.Errorf("body has no reset link: %s", messages[0].Body)
+	}
+}
+
+func TestSendResetLinkUnknownEmail(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	sender := mailer.NewMemorySender()
+	s := newResetService(db, sender)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "owner" WHERE email = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if err := s.sendResetLink(context.Background(), "nobody@example.com"); err != nil {
+		t.Fatalf("sendResetLink() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if messages := sender.Messages(); len(messages) != 0 {
+		t.Errorf("sent %+v, want nothing", messages)
+	}
+}
+
+// the response must not wait for the lookup, otherwise known emails answer slower
+func TestResetPasswordDoesNotWait(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	sender := mailer.NewMemorySender()
+	s := newResetService(db, sender)
+
+	ownerId := uuid.New()
+	expectOwnerByEmail(mock, "owner@example.com", ownerId).WillDelayFor(200 * time.Millisecond)
+	expectResetToken(mock, ownerId)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	if err := s.ResetPassword(ctx, ResetPasswordReq{Email: "owner@example.com"}); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if took := time.Since(start); took >= 200*time.Millisecond {
+		t.Errorf("ResetPassword() took %s, it waited for the lookup", took)
+	}
+	// the request ending must not stop the email
+	cancel()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(sender.Messages()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(sender.Messages()) != 1 {
+		t.Fatal("the reset email was not sent")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
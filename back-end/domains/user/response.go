@@ -6,10 +6,35 @@ import (
 	"github.com/devanadindra/portfolio/back-end/utils/constants"
 )
 
+// LoginRes has no token yet when TwoFactorRequired is set, the challenge token is sent to
+// the second login step together with the code. The refresh token is only ever sent in its
+// http only cookie, never in the body.
 type LoginRes struct {
-	Role    constants.ROLE `json:"role"`
-	Token   string         `json:"token"`
-	Expires time.Time      `json:"expires"`
+	Role              constants.ROLE `json:"role"`
+	Token             string         `json:"token"`
+	Expires           time.Time      `json:"expires"`
+	RefreshToken      string         `json:"-"`
+	RefreshExpires    *time.Time     `json:"-"`
+	TwoFactorRequired bool           `json:"twoFactorRequired"`
+	ChallengeToken    string         `json:"challengeToken,omitempty"`
+}
+
+type TwoFactorStatusRes struct {
+	Enabled           bool       `json:"enabled"`
+	EnabledAt         *time.Time `json:"enabledAt"`
+	RecoveryCodesLeft int64      `json:"recoveryCodesLeft"`
+}
+
+type TwoFactorEnrollRes struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+	// data url of the provisioning uri as a QR code
+	QrPng string `json:"qrPng"`
+}
+
+// RecoveryCodesRes is the only time the recovery codes are shown.
+type RecoveryCodesRes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
 }
 
 type VerifyTokenRes struct {
@@ -20,8 +45,14 @@ type LogoutRes struct {
 	LoggedOut bool `json:"loggedOut"`
 }
 
-type ResetPasswordRes struct {
-	Email string
+type AvatarRes struct {
+	AvatarUrl string             `json:"avatarUrl"`
+	Variants  []AvatarVariantRes `json:"variants"`
+}
+
+type AvatarVariantRes struct {
+	Size int    `json:"size"`
+	Url  string `json:"url"`
 }
 
 type AboutRes struct {
@@ -33,6 +64,13 @@ type AboutRes struct {
 	Slogan    string
 	ImgUrl    string
 	Skills    []SkillRes
+	// the same skills grouped by category, categories in display order
+	SkillCategories []SkillCategoryRes
+}
+
+type SkillCategoryRes struct {
+	Name   string
+	Skills []SkillRes
 }
 
 type SkillRes struct {
@@ -41,4 +79,8 @@ type SkillRes struct {
 	Experience int
 	Period     string
 	ImgUrl     string
+	// Experience and Period are computed from these
+	StartedAt       time.Time
+	EndedAt         *time.Time
+	ExperienceLabel string
 }
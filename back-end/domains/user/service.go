@@ -3,24 +3,22 @@ package user
 import (
 	"context"
 	"errors"
-	"fmt"
 	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/domains/skill"
 	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
 	"github.com/devanadindra/portfolio/back-end/utils/config"
 	"github.com/devanadindra/portfolio/back-end/utils/constants"
 	contextUtil "github.com/devanadindra/portfolio/back-end/utils/context"
 	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
-	fileutils "github.com/devanadindra/portfolio/back-end/utils/file"
+	"github.com/devanadindra/portfolio/back-end/utils/mailer"
 )
 
 type Service interface {
@@ -28,42 +26,54 @@ type Service interface {
 	Logout(ctx context.Context, input LogoutReq) (res *LogoutRes, err error)
 	ValidateToken(ctx context.Context, token string) error
 	ChangePassword(ctx context.Context, input ChangePasswordReq) error
-	ResetPasswordSubmit(ctx context.Context, req ResetPasswordSubmitReq) (err error)
-	ResetPassword(ctx context.Context, req ResetPasswordReq) (res *ResetPasswordRes, err error)
-	AddAvatar(ctx context.Context, req AvatarReq) (string, error)
+	ResetPasswordSubmit(ctx context.Context, req ResetPasswordSubmitReq) error
+	ResetPassword(ctx context.Context, req ResetPasswordReq) error
+	AddAvatar(ctx context.Context, req AvatarReq) (*AvatarRes, error)
+	LoginTwoFactor(ctx context.Context, input LoginTwoFactorReq) (*LoginRes, error)
+	Refresh(ctx context.Context, input RefreshReq) (*LoginRes, error)
+	GetTwoFactorStatus(ctx context.Context) (*TwoFactorStatusRes, error)
+	EnrollTwoFactor(ctx context.Context) (*TwoFactorEnrollRes, error)
+	ConfirmTwoFactor(ctx context.Context, input ConfirmTwoFactorReq) (*RecoveryCodesRes, error)
+	DisableTwoFactor(ctx context.Context, input TwoFactorPasswordReq) error
+	RegenerateRecoveryCodes(ctx context.Context, input TwoFactorPasswordReq) (*RecoveryCodesRes, error)
 	GetAbout(ctx context.Context) (*AboutRes, error)
+	UpdateAbout(ctx context.Context, input UpdateAboutReq) (*AboutRes, error)
 }
 
 type service struct {
-	authConfig config.Auth
-	dbSelector *dbselector.DBService
-	VisitorsDB *database.VisitorsDB
-	OwnerDB    *database.OwnerDB
+	authConfig   config.Auth
+	totpConfig   config.TOTP
+	avatarConfig config.Avatar
+	mailer       mailer.Sender
+	dbSelector   *dbselector.DBService
+	VisitorsDB   *database.VisitorsDB
+	OwnerDB      *database.OwnerDB
 }
 
-func NewService(config *config.Config, dbSelector *dbselector.DBService, VisitorsDB *database.VisitorsDB, OwnerDB *database.OwnerDB) Service {
+func NewService(config *config.Config, dbSelector *dbselector.DBService, VisitorsDB *database.VisitorsDB, OwnerDB *database.OwnerDB, mailer mailer.Sender) Service {
 	return &service{
-		authConfig: config.Auth,
-		dbSelector: dbSelector,
-		VisitorsDB: VisitorsDB,
-		OwnerDB:    OwnerDB,
+		authConfig:   config.Auth,
+		totpConfig:   config.Auth.TOTP,
+		avatarConfig: config.Avatar,
+		mailer:       mailer,
+		dbSelector:   dbSelector,
+		VisitorsDB:   VisitorsDB,
+		OwnerDB:      OwnerDB,
 	}
 }
 
 func (s *service) Login(ctx context.Context, input LoginReq, w http.ResponseWriter) (*LoginRes, error) {
 	var err error
-	var userID uuid.UUID
+	var owner Owner
 
 	switch input.Role {
 	case constants.OWNER:
-		var owner Owner
 		db := s.OwnerDB.DB
 		err = db.WithContext(ctx).Where("email = ?", input.Email).First(&owner).Error
 		if err == nil {
 			if !comparePassword(owner.Password, input.Password) {
 				return nil, apierror.NewWarn(http.StatusUnauthorized, ErrInvalidCredentials)
 			}
-			userID = owner.ID
 		}
 	default:
 		return nil, apierror.NewWarn(http.StatusBadRequest, "role tidak valid")
@@ -76,11 +86,22 @@ func (s *service) Login(ctx context.Context, input LoginReq, w http.ResponseWrit
 		return nil, apierror.FromErr(err)
 	}
 
-	expirationTime := time.Now().Add(s.authConfig.JWT.ExpireIn)
+	if owner.TotpEnabledAt != nil {
+		return s.createLoginChallenge(ctx, owner)
+	}
+
+	return s.issueToken(s.OwnerDB.DB.WithContext(ctx), owner, input.Role, uuid.New())
+}
+
+// issueToken signs a short lived access token and stores the next refresh token of the
+// family, a new login starts a new family.
+func (s *service) issueToken(tx *gorm.DB, owner Owner, role constants.ROLE, familyID uuid.UUID) (*LoginRes, error) {
+	now := time.Now()
+	expirationTime := now.Add(s.authConfig.JWT.ExpireIn)
 	claims := &constants.JWTClaims{
-		UserID: userID,
-		Email:  input.Email,
-		Role:   input.Role,
+		UserID: owner.ID,
+		Email:  owner.Email,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 		},
@@ -92,25 +113,56 @@ func (s *service) Login(ctx context.Context, input LoginReq, w http.ResponseWrit
 		return nil, apierror.FromErr(err)
 	}
 
+	refreshToken, err := newToken()
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	// a good moment to drop the owner's refresh tokens nobody can use anymore
+	if err := tx.Where("owner_id = ? AND expires_at < ?", owner.ID, now).
+		Delete(&RefreshToken{}).Error; err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	refresh := RefreshToken{
+		OwnerID:   owner.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(s.authConfig.JWT.RefreshExpireIn),
+	}
+	if err := tx.Create(&refresh).Error; err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
 	return &LoginRes{
-		Role:    input.Role,
-		Token:   tokenString,
-		Expires: expirationTime,
+		Role:           role,
+		Token:          tokenString,
+		Expires:        expirationTime,
+		RefreshToken:   refreshToken,
+		RefreshExpires: &refresh.ExpiresAt,
 	}, nil
 }
 
 func (s *service) Logout(ctx context.Context, input LogoutReq) (res *LogoutRes, err error) {
-	db, err := s.dbSelector.GetDBByRole(ctx)
-	if err != nil {
-		return nil, err
+	if input.Token != "" {
+		db, err := s.dbSelector.GetDBByRole(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		err = db.WithContext(ctx).Create(InvalidToken{
+			Token:   input.Token,
+			Expires: input.Expires,
+		}).Error
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	err = db.WithContext(ctx).Create(InvalidToken{
-		Token:   input.Token,
-		Expires: input.Expires,
-	}).Error
-	if err != nil {
-		return nil, err
+	if input.RefreshToken != "" {
+		if err := s.revokeFamilyOf(s.OwnerDB.DB.WithContext(ctx), input.RefreshToken); err != nil {
+			return nil, apierror.FromErr(err)
+		}
 	}
 
 	return &LogoutRes{
@@ -135,6 +187,8 @@ func (s *service) ValidateToken(ctx context.Context, token string) error {
 	return errors.New("token is blacklisted")
 }
 
+// ChangePassword checks the current password before setting the new one. Every other session
+// of the owner is signed out, the one making the change stays.
 func (s *service) ChangePassword(ctx context.Context, input ChangePasswordReq) error {
 	token, err := contextUtil.GetTokenClaims(ctx)
 	if err != nil {
@@ -156,16 +210,24 @@ func (s *service) ChangePassword(ctx context.Context, input ChangePasswordReq) e
 
 	switch role {
 	case constants.OWNER:
-		var owner Owner
-		if err = db.WithContext(ctx).Where("id = ?", userID).First(&owner).Error; err == nil {
+		err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var owner Owner
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", userID).
+				First(&owner).Error; err != nil {
+				return err
+			}
 			if !comparePassword(owner.Password, input.CurrentPassword) {
 				return apierror.NewWarn(http.StatusUnauthorized, ErrInvalidCurPassword)
 			}
+
 			owner.Password = hashedPassword
-			if err = db.WithContext(ctx).Save(&owner).Error; err != nil {
+			if err := tx.Save(&owner).Error; err != nil {
 				return err
 			}
-		}
+			// the other sessions have to sign in again with the new password
+			return revokeOwnerSessions(tx, owner.ID, input.RefreshToken)
+		})
 	}
 
 	if err != nil {
@@ -178,138 +240,23 @@ func (s *service) ChangePassword(ctx context.Context, input ChangePasswordReq) e
 	return nil
 }
 
-func (s *service) AddAvatar(ctx context.Context, req AvatarReq) (string, error) {
-	token, err := contextUtil.GetTokenClaims(ctx)
-	if err != nil {
-		return "", err
-	}
+func (s *service) GetAbout(ctx context.Context) (*AboutRes, error) {
 	db, err := s.dbSelector.GetDBByRole(ctx)
 	if err != nil {
-		return "", err
-	}
-
-	userID := token.Claims.UserID
-	role := token.Claims.Role
-
-	var oldAvatar string
-	switch role {
-	case constants.OWNER:
-		var owner Owner
-		err = db.WithContext(ctx).Where("id = ?", userID).First(&owner).Error
-		if err != nil {
-			return "", apierror.FromErr(err)
-		}
-		oldAvatar = owner.AvatarUrl
-	}
-
-	file := req.AvatarUrl
-	ext := filepath.Ext(file.Filename)
-
-	filename, err := fileutils.GenerateMediaName(userID.String())
-	if err != nil {
-		return "", apierror.FromErr(err)
-	}
-
-	filename = fmt.Sprintf("%s%s", filename, ext)
-	newPath := filepath.Join("uploads", "avatars", filename)
-
-	if err := os.MkdirAll(filepath.Dir(newPath), os.ModePerm); err != nil {
-		return "", apierror.FromErr(err)
-	}
-
-	if err := fileutils.SaveMedia(ctx, file, newPath); err != nil {
-		return "", err
-	}
-
-	newUrl := "/uploads/avatars/" + filename
-	switch role {
-	case constants.OWNER:
-		if err := db.WithContext(ctx).Model(&Owner{}).
-			Where("id = ?", userID).
-			Update("avatar_url", newUrl).Error; err != nil {
-			return "", err
-		}
-	}
-
-	if oldAvatar != "" {
-		oldPath := strings.TrimPrefix(oldAvatar, "/")
-		if err := os.Remove(oldPath); err != nil && !os.IsNotExist(err) {
-			return "", apierror.FromErr(err)
-		}
-	}
-
-	return newUrl, nil
-}
-
-func (s *service) ResetPassword(ctx context.Context, req ResetPasswordReq) (res *ResetPasswordRes, err error) {
-	db := s.OwnerDB.DB
-
-	switch req.Role {
-	case constants.OWNER:
-		var owner Owner
-		err = db.WithContext(ctx).
-			Where("email = ?", req.Email).
-			First(&owner).Error
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("admin not found")
-		} else if err != nil {
-			return nil, err
-		}
-		res = &ResetPasswordRes{
-			Email: owner.Email,
-		}
-		return res, nil
-	default:
-		return nil, errors.New("invalid role")
+		return nil, err
 	}
-}
-
-func (s *service) ResetPasswordSubmit(ctx context.Context, req ResetPasswordSubmitReq) (err error) {
-	db := s.OwnerDB.DB
 
-	hashedPassword, err := hashPassword(req.NewPassword)
+	about, err := findAbout(db.WithContext(ctx))
 	if err != nil {
-		return apierror.FromErr(err)
+		return nil, apierror.FromErr(err)
 	}
 
-	switch req.Role {
-	case constants.OWNER:
-		var owner Owner
-		err = db.WithContext(ctx).
-			Where("email = ?", req.Email).
-			First(&owner).Error
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return err
-		} else if err != nil {
-			return err
-		}
-		owner.Password = hashedPassword
-		if err = db.WithContext(ctx).Save(&owner).Error; err != nil {
-			return err
-		}
-
-		return nil
-	default:
-		return nil
-	}
+	return toAboutRes(db.WithContext(ctx), about)
 }
 
-func (s *service) GetAbout(ctx context.Context) (*AboutRes, error) {
-	db, err := s.dbSelector.GetDBByRole(ctx)
-	if err != nil {
-		return nil, err
-	}
-
+// toAboutRes builds the about page from the row with its skills preloaded.
+func toAboutRes(db *gorm.DB, about About) (*AboutRes, error) {
 	res := &AboutRes{}
-
-	var about About
-	err = db.WithContext(ctx).
-		Preload("Skills").
-		First(&about).Error
-	if err != nil {
-		return nil, err
-	}
-
 	res.Name = about.Name
 	res.Nim = about.Nim
 	res.Major = about.Major
@@ -320,13 +267,24 @@ func (s *service) GetAbout(ctx context.Context) (*AboutRes, error) {
 
 	res.Skills = make([]SkillRes, 0, len(about.Skills))
 	for _, sk := range about.Skills {
-		res.Skills = append(res.Skills, SkillRes{
-			Name:       sk.Name,
-			Ratio:      sk.Ratio,
-			Experience: sk.Experience,
-			Period:     sk.Period,
-			ImgUrl:     sk.ImgUrl,
-		})
+		res.Skills = append(res.Skills, toSkillRes(skill.ToSkillRes(sk)))
+	}
+
+	var categories []skill.SkillCategory
+	if err := db.Order("position ASC, name ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	groups := skill.GroupSkills(categories, about.Skills)
+	res.SkillCategories = make([]SkillCategoryRes, len(groups))
+	for i, group := range groups {
+		res.SkillCategories[i] = SkillCategoryRes{
+			Name:   group.Name,
+			Skills: make([]SkillRes, len(group.Skills)),
+		}
+		for j, sk := range group.Skills {
+			res.SkillCategories[i].Skills[j] = toSkillRes(sk)
+		}
 	}
 
 	return res, nil
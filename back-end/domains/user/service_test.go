@@ -0,0 +1,68 @@
+package user
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+func expectLockedOwner(t *testing.T, mock sqlmock.Sqlmock, ownerId uuid.UUID, password string) {
+	t.Helper()
+
+	hashed, err := hashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "owner" WHERE id = $1 ORDER BY "owner"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(ownerId, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password"}).AddRow(ownerId, hashed))
+}
+
+func TestChangePassword(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	s := newOwnerService(db)
+
+	ownerId := uuid.New()
+	mock.ExpectBegin()
+	expectLockedOwner(t, mock, ownerId, "old-password")
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "owner" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRevokeOwnerSessions(mock, ownerId, "current")
+	mock.ExpectCommit()
+
+	err := s.ChangePassword(ownerContext(ownerId), ChangePasswordReq{
+		CurrentPassword: "old-password",
+		NewPassword:     "new-password",
+		RefreshToken:    "current",
+	})
+	if err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// a wrong current password rolls back and leaves the sessions alone
+func TestChangePasswordWrongPassword(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	s := newOwnerService(db)
+
+	ownerId := uuid.New()
+	mock.ExpectBegin()
+	expectLockedOwner(t, mock, ownerId, "old-password")
+	mock.ExpectRollback()
+
+	err := s.ChangePassword(ownerContext(ownerId), ChangePasswordReq{CurrentPassword: "wrong", NewPassword: "new-password"})
+	if got := testutil.StatusOf(err); got != http.StatusUnauthorized {
+		t.Fatalf("ChangePassword() status = %d (%v), want 401", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
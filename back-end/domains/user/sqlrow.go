@@ -23,14 +23,88 @@ type Owner struct {
 	Password  string
 	Email     string `gorm:"unique"`
 	AvatarUrl string
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	// two factor is on once TotpEnabledAt is set, before that the secret is still being enrolled
+	TotpSecret      *string
+	TotpEnabledAt   *time.Time
+	TotpLastCounter int64
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
 }
 
 func (Owner) TableName() string {
 	return "owner"
 }
 
+// OwnerRecoveryCode is a one-time code that stands in for a totp code, only its sha256 is kept.
+type OwnerRecoveryCode struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	OwnerID   uuid.UUID `gorm:"type:uuid"`
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (OwnerRecoveryCode) TableName() string {
+	return "owner_recovery_codes"
+}
+
+// LoginChallenge is a password login waiting for the second factor.
+type LoginChallenge struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	OwnerID   uuid.UUID `gorm:"type:uuid"`
+	TokenHash string
+	Attempts  int
+	ExpiresAt time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (LoginChallenge) TableName() string {
+	return "login_challenges"
+}
+
+// PasswordResetToken only stores the sha256 of the token that was emailed.
+type PasswordResetToken struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	OwnerID   uuid.UUID `gorm:"type:uuid"`
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
+
+// RefreshToken only stores the sha256 of the token handed out. Each refresh uses it up and
+// issues the next token of the same family.
+type RefreshToken struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	OwnerID   uuid.UUID `gorm:"type:uuid"`
+	FamilyID  uuid.UUID `gorm:"type:uuid"`
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// OwnerAvatar is one square size of the owner's current avatar.
+type OwnerAvatar struct {
+	OwnerID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Size      int       `gorm:"primaryKey"`
+	Url       string
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (OwnerAvatar) TableName() string {
+	return "owner_avatars"
+}
+
 type About struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	Name      string
@@ -0,0 +1,385 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	contextUtil "github.com/devanadindra/portfolio/back-end/utils/context"
+	"github.com/devanadindra/portfolio/back-end/utils/totp"
+)
+
+const (
+	qrSize              = 256
+	recoveryCodeLength  = 10
+	recoveryCodeCharset = "abcdefghijklmnopqrstuvwxyz234567"
+)
+
+func (s *service) GetTwoFactorStatus(ctx context.Context) (*TwoFactorStatusRes, error) {
+	db := s.OwnerDB.DB.WithContext(ctx)
+
+	owner, err := s.currentOwner(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &TwoFactorStatusRes{
+		Enabled:   owner.TotpEnabledAt != nil,
+		EnabledAt: owner.TotpEnabledAt,
+	}
+	if err := db.Model(&OwnerRecoveryCode{}).
+		Where("owner_id = ? AND used_at IS NULL", owner.ID).
+		Count(&res.RecoveryCodesLeft).Error; err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return res, nil
+}
+
+// EnrollTwoFactor starts over with a new secret. Two factor stays off until a code for the
+// secret is confirmed, so an abandoned enrollment never locks the owner out.
+func (s *service) EnrollTwoFactor(ctx context.Context) (*TwoFactorEnrollRes, error) {
+	db := s.OwnerDB.DB.WithContext(ctx)
+
+	owner, err := s.currentOwner(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if owner.TotpEnabledAt != nil {
+		return nil, apierror.TwoFactorAlreadyEnabled()
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	uri := totp.URI(s.totpConfig.Issuer, owner.Email, secret)
+	qr, err := totp.QRPNG(uri, qrSize)
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	if err := db.Model(&owner).Update("totp_secret", secret).Error; err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return &TwoFactorEnrollRes{
+		Secret: secret,
+		Uri:    uri,
+		QrPng:  "data:image/png;base64," + base64.StdEncoding.EncodeToString(qr),
+	}, nil
+}
+
+// ConfirmTwoFactor turns two factor on once the authenticator app gives a valid code and
+// returns the first set of recovery codes. Every other session is ended.
+func (s *service) ConfirmTwoFactor(ctx context.Context, input ConfirmTwoFactorReq) (*RecoveryCodesRes, error) {
+	var codes []string
+	err := s.OwnerDB.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		owner, err := s.currentOwner(ctx, tx.Clauses(clause.Locking{Strength: "UPDATE"}))
+		if err != nil {
+			return err
+		}
+		if owner.TotpEnabledAt != nil {
+			return apierror.TwoFactorAlreadyEnabled()
+		}
+		if owner.TotpSecret == nil {
+			return apierror.TwoFactorNotEnrolled()
+		}
+
+		now := time.Now()
+		counter, ok := totp.Validate(*owner.TotpSecret, input.Code, now, s.totpConfig.Skew)
+		if !ok {
+			return apierror.InvalidTwoFactorCode()
+		}
+
+		if err := tx.Model(&owner).Updates(map[string]any{
+			"totp_enabled_at":   now,
+			"totp_last_counter": counter,
+		}).Error; err != nil {
+			return err
+		}
+
+		codes, err = s.replaceRecoveryCodes(tx, owner)
+		if err != nil {
+			return err
+		}
+
+		// sessions started with the password alone have to sign in with the code
+		return revokeOwnerSessions(tx, owner.ID, input.RefreshToken)
+	})
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return &RecoveryCodesRes{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor turns two factor off and ends every other session, the password alone
+// signs in again from now on.
+func (s *service) DisableTwoFactor(ctx context.Context, input TwoFactorPasswordReq) error {
+	err := s.OwnerDB.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		owner, err := s.currentOwnerWithPassword(ctx, tx, input.Password)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&owner).Updates(map[string]any{
+			"totp_secret":       nil,
+			"totp_enabled_at":   nil,
+			"totp_last_counter": 0,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("owner_id = ?", owner.ID).Delete(&OwnerRecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("owner_id = ?", owner.ID).Delete(&LoginChallenge{}).Error; err != nil {
+			return err
+		}
+
+		return revokeOwnerSessions(tx, owner.ID, input.RefreshToken)
+	})
+	if err != nil {
+		return apierror.FromErr(err)
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code, used or not, with a new set.
+func (s *service) RegenerateRecoveryCodes(ctx context.Context, input TwoFactorPasswordReq) (*RecoveryCodesRes, error) {
+	var codes []string
+	err := s.OwnerDB.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		owner, err := s.currentOwnerWithPassword(ctx, tx, input.Password)
+		if err != nil {
+			return err
+		}
+
+		codes, err = s.replaceRecoveryCodes(tx, owner)
+		return err
+	})
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return &RecoveryCodesRes{RecoveryCodes: codes}, nil
+}
+
+// LoginTwoFactor is the second login step. The code is either a totp code or a recovery code,
+// each can only be used once. After too many wrong codes the challenge stops working and the
+// login has to start again with the password. It is kept until it expires so it still counts
+// towards the open challenges.
+func (s *service) LoginTwoFactor(ctx context.Context, input LoginTwoFactorReq) (*LoginRes, error) {
+	var owner Owner
+	var codeErr error
+	err := s.OwnerDB.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var challenge LoginChallenge
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND expires_at > ? AND attempts < ?", hashToken(input.ChallengeToken), now, s.totpConfig.MaxAttempts).
+			First(&challenge).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apierror.InvalidLoginChallenge()
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", challenge.OwnerID).
+			First(&owner).Error; err != nil {
+			return err
+		}
+		if owner.TotpEnabledAt == nil || owner.TotpSecret == nil {
+			// two factor was turned off in the meantime, the password login is enough
+			return tx.Delete(&challenge).Error
+		}
+
+		ok, err := s.useSecondFactor(tx, owner, input.Code, now)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return tx.Delete(&challenge).Error
+		}
+
+		// the failed attempt is committed, only the response is an error
+		codeErr = apierror.InvalidTwoFactorCode()
+		challenge.Attempts++
+		if challenge.Attempts >= s.totpConfig.MaxAttempts {
+			codeErr = apierror.InvalidLoginChallenge()
+		}
+		return tx.Model(&challenge).Update("attempts", challenge.Attempts).Error
+	})
+	if err == nil {
+		err = codeErr
+	}
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return s.issueToken(s.OwnerDB.DB.WithContext(ctx), owner, constants.OWNER, uuid.New())
+}
+
+// createLoginChallenge holds a correct password login until the second factor is given. Only
+// a few challenges can be open at once, otherwise the password alone would give an unlimited
+// number of code guesses.
+func (s *service) createLoginChallenge(ctx context.Context, owner Owner) (*LoginRes, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	now := time.Now()
+	challenge := LoginChallenge{
+		OwnerID:   owner.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(s.totpConfig.ChallengeTTL),
+	}
+
+	err = s.OwnerDB.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// concurrent logins wait here so they cannot both pass the count
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", owner.ID).
+			First(&Owner{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("owner_id = ? AND expires_at < ?", owner.ID, now).
+			Delete(&LoginChallenge{}).Error; err != nil {
+			return err
+		}
+
+		var open int64
+		if err := tx.Model(&LoginChallenge{}).
+			Where("owner_id = ?", owner.ID).
+			Count(&open).Error; err != nil {
+			return err
+		}
+		if open >= int64(s.totpConfig.MaxChallenges) {
+			return apierror.TooManyLoginChallenges()
+		}
+
+		return tx.Create(&challenge).Error
+	})
+	if err != nil {
+		return nil, apierror.FromErr(err)
+	}
+
+	return &LoginRes{
+		Role:              constants.OWNER,
+		Expires:           challenge.ExpiresAt,
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+	}, nil
+}
+
+// useSecondFactor checks the code and marks it as used. The owner row must be locked.
+func (s *service) useSecondFactor(tx *gorm.DB, owner Owner, code string, now time.Time) (bool, error) {
+	normalized := normalizeRecoveryCode(code)
+	if len(normalized) == recoveryCodeLength {
+		result := tx.Model(&OwnerRecoveryCode{}).
+			Where("owner_id = ? AND code_hash = ? AND used_at IS NULL", owner.ID, hashToken(normalized)).
+			Update("used_at", now)
+		return result.RowsAffected == 1, result.Error
+	}
+
+	counter, ok := totp.Validate(*owner.TotpSecret, code, now, s.totpConfig.Skew)
+	if !ok || counter <= owner.TotpLastCounter {
+		return false, nil
+	}
+
+	return true, tx.Model(&owner).Update("totp_last_counter", counter).Error
+}
+
+func (s *service) replaceRecoveryCodes(tx *gorm.DB, owner Owner) ([]string, error) {
+	if err := tx.Where("owner_id = ?", owner.ID).Delete(&OwnerRecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, s.totpConfig.RecoveryCodes)
+	rows := make([]OwnerRecoveryCode, len(codes))
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		rows[i] = OwnerRecoveryCode{OwnerID: owner.ID, CodeHash: hashToken(normalizeRecoveryCode(code))}
+	}
+
+	if len(rows) > 0 {
+		if err := tx.Create(&rows).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return codes, nil
+}
+
+func (s *service) currentOwner(ctx context.Context, db *gorm.DB) (Owner, error) {
+	var owner Owner
+
+	token, err := contextUtil.GetTokenClaims(ctx)
+	if err != nil {
+		return owner, err
+	}
+
+	if err := db.Where("id = ?", token.Claims.UserID).First(&owner).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return owner, apierror.Unauthorized()
+		}
+		return owner, err
+	}
+
+	return owner, nil
+}
+
+// currentOwnerWithPassword locks the owner and checks the password, two factor must be on.
+func (s *service) currentOwnerWithPassword(ctx context.Context, tx *gorm.DB, password string) (Owner, error) {
+	owner, err := s.currentOwner(ctx, tx.Clauses(clause.Locking{Strength: "UPDATE"}))
+	if err != nil {
+		return owner, err
+	}
+
+	if !comparePassword(owner.Password, password) {
+		return owner, apierror.NewWarn(http.StatusUnauthorized, ErrInvalidCurPassword)
+	}
+	if owner.TotpEnabledAt == nil {
+		return owner, apierror.TwoFactorNotEnabled()
+	}
+
+	return owner, nil
+}
+
+// newRecoveryCode returns a code like "k3m9x-2pq7f", 50 random bits.
+func newRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, len(b))
+	for i, v := range b {
+		code[i] = recoveryCodeCharset[int(v)%len(recoveryCodeCharset)]
+	}
+	return string(code[:5]) + "-" + string(code[5:]), nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+}
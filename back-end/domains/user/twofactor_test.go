@@ -0,0 +1,178 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+	"github.com/devanadindra/portfolio/back-end/utils/totp"
+)
+
+// "12345678901234567890" base32 encoded, the seed of the RFC 6238 test vectors
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func newTwoFactorService(db *gorm.DB) *service {
+	return &service{
+		totpConfig: config.TOTP{Skew: 1, ChallengeTTL: 5 * time.Minute, MaxAttempts: 5, MaxChallenges: 3},
+		OwnerDB:    &database.OwnerDB{DB: db},
+	}
+}
+
+func expectChallengeCount(mock sqlmock.Sqlmock, ownerId uuid.UUID, open int) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "owner" WHERE id = $1 ORDER BY "owner"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(ownerId, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(ownerId))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "login_challenges" WHERE owner_id = $1 AND expires_at < $2`)).
+		WithArgs(ownerId, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "login_challenges" WHERE owner_id = $1`)).
+		WithArgs(ownerId).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(open))
+}
+
+func TestCreateLoginChallenge(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	s := newTwoFactorService(db)
+
+	ownerId := uuid.New()
+	expectChallengeCount(mock, ownerId, 2)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "login_challenges"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	res, err := s.createLoginChallenge(context.Background(), Owner{ID: ownerId})
+	if err != nil {
+		t.Fatalf("createLoginChallenge() error = %v", err)
+	}
+	if !res.TwoFactorRequired || res.ChallengeToken == "" || res.Token != "" {
+		t.Errorf("unexpected response %+v", res)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateLoginChallengeLimit(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	s := newTwoFactorService(db)
+
+	ownerId := uuid.New()
+	expectChallengeCount(mock, ownerId, 3)
+	mock.ExpectRollback()
+
+	_, err := s.createLoginChallenge(context.Background(), Owner{ID: ownerId})
+	if got := testutil.StatusOf(err); got != http.StatusTooManyRequests {
+		t.Fatalf("createLoginChallenge() status = %d (%v), want 429", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// a used up challenge is kept so it still counts towards the limit until it expires
+func TestLoginTwoFactorKeepsUsedUpChallenge(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	s := newTwoFactorService(db)
+
+	ownerId, challengeId := uuid.New(), uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "login_challenges" WHERE token_hash = $1 AND expires_at > $2 AND attempts < $3`)).
+		WithArgs(hashToken("challenge"), sqlmock.AnyArg(), 5, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner_id", "attempts"}).AddRow(challengeId, ownerId, 4))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "owner" WHERE id = $1`)).
+		WithArgs(ownerId, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "totp_secret", "totp_enabled_at"}).
+			AddRow(ownerId, rfcSecret, time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "login_challenges" SET "attempts"=$1 WHERE "id" = $2`)).
+		WithArgs(5, challengeId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	_, err := s.LoginTwoFactor(context.Background(), LoginTwoFactorReq{ChallengeToken: "challenge", Code: "abcdef"})
+	if got := testutil.StatusOf(err); got != http.StatusUnauthorized {
+		t.Fatalf("LoginTwoFactor() status = %d (%v), want 401", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfirmTwoFactorEndsOtherSessions(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	s := newTwoFactorService(db)
+	s.totpConfig.RecoveryCodes = 2
+
+	ownerId := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "owner" WHERE id = $1 ORDER BY "owner"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(ownerId, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "totp_secret"}).AddRow(ownerId, rfcSecret))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "owner" SET "totp_enabled_at"=$1,"totp_last_counter"=$2`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "owner_recovery_codes" WHERE owner_id = $1`)).
+		WithArgs(ownerId).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "owner_recovery_codes"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()).AddRow(uuid.New()))
+	expectRevokeOwnerSessions(mock, ownerId, "current")
+	mock.ExpectCommit()
+
+	code, err := totp.Code(rfcSecret, totp.Counter(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := s.ConfirmTwoFactor(ownerContext(ownerId), ConfirmTwoFactorReq{Code: code, RefreshToken: "current"})
+	if err != nil {
+		t.Fatalf("ConfirmTwoFactor() error = %v", err)
+	}
+	if len(res.RecoveryCodes) != 2 {
+		t.Errorf("got %d recovery codes, want 2", len(res.RecoveryCodes))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDisableTwoFactorEndsOtherSessions(t *testing.T) {
+	db, mock := testutil.NewMockDB(t)
+	s := newTwoFactorService(db)
+
+	hashed, err := hashPassword("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ownerId := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "owner" WHERE id = $1 ORDER BY "owner"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(ownerId, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password", "totp_secret", "totp_enabled_at"}).
+			AddRow(ownerId, hashed, rfcSecret, time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "owner" SET "totp_enabled_at"=$1,"totp_last_counter"=$2,"totp_secret"=$3`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "owner_recovery_codes" WHERE owner_id = $1`)).
+		WithArgs(ownerId).
+		WillReturnResult(sqlmock.NewResult(0, 10))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "login_challenges" WHERE owner_id = $1`)).
+		WithArgs(ownerId).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectRevokeOwnerSessions(mock, ownerId, "current")
+	mock.ExpectCommit()
+
+	if err := s.DisableTwoFactor(ownerContext(ownerId), TwoFactorPasswordReq{Password: "password", RefreshToken: "current"}); err != nil {
+		t.Fatalf("DisableTwoFactor() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
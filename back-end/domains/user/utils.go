@@ -1,10 +1,18 @@
 package user
 
 import (
+	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
+
+	"github.com/devanadindra/portfolio/back-end/domains/skill"
+	fileutils "github.com/devanadindra/portfolio/back-end/utils/file"
+	"github.com/devanadindra/portfolio/back-end/utils/logger"
 )
 
 func comparePassword(storedHash, plain string) bool {
@@ -31,3 +39,51 @@ func randomPassword() string {
 
 	return hashed
 }
+
+func toSkillRes(sk skill.SkillRes) SkillRes {
+	return SkillRes{
+		Name:            sk.Name,
+		Ratio:           sk.Ratio,
+		Experience:      sk.Experience,
+		Period:          sk.Period,
+		ImgUrl:          sk.ImgUrl,
+		StartedAt:       sk.StartedAt,
+		EndedAt:         sk.EndedAt,
+		ExperienceLabel: sk.ExperienceLabel,
+	}
+}
+
+// removeMediaFiles deletes files from disk, failures are only logged since the database
+// change has already been decided.
+func removeMediaFiles(ctx context.Context, urls ...string) {
+	for _, url := range urls {
+		if err := fileutils.RemoveMedia(url); err != nil {
+			logger.Error(ctx, "%v", err)
+		}
+	}
+}
+
+// newToken returns 32 random bytes, url safe so it can go into links and cookies as is.
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is how tokens and codes handed out to the owner are stored. They are random
+// enough that a plain sha256 is sufficient and lets them be looked up directly.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// trimmed trims an optional form value, a field that was not sent stays nil.
+func trimmed(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	return &trimmed
+}
@@ -0,0 +1,17 @@
+package jobs
+
+import (
+	"github.com/devanadindra/portfolio/back-end/domains/certif"
+	"github.com/devanadindra/portfolio/back-end/domains/linkcheck"
+	"github.com/devanadindra/portfolio/back-end/domains/trash"
+	"github.com/devanadindra/portfolio/back-end/utils/scheduler"
+)
+
+// NewScheduler collects the background jobs of every domain, they are started with the server.
+func NewScheduler(trashService trash.Service, certifService certif.Service, linkCheckService linkcheck.Service) *scheduler.Scheduler {
+	return scheduler.New(
+		trashService.PurgeJob(),
+		certifService.ExpiryReminderJob(),
+		linkCheckService.LinkCheckJob(),
+	)
+}
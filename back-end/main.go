@@ -55,6 +55,8 @@ func main() {
 		Handler: handlerWithCORS,
 	}
 
+	dependency.StartJobs(ctx)
+
 	go func() {
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			panic(fmt.Sprintf("Error listen and serve : %v\n", err))
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS uq_project_images_cover;
+DROP INDEX IF EXISTS idx_project_images_position;
+
+ALTER TABLE project_images
+    DROP COLUMN IF EXISTS is_cover,
+    DROP COLUMN IF EXISTS position;
@@ -0,0 +1,25 @@
+ALTER TABLE project_images
+    ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS is_cover BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE project_images pi
+SET
+    position = ordered.rn - 1
+FROM
+    (
+        SELECT
+            id,
+            ROW_NUMBER() OVER (
+                PARTITION BY project_id
+                ORDER BY created_at, id
+            ) AS rn
+        FROM project_images
+    ) ordered
+WHERE
+    pi.id = ordered.id;
+
+CREATE INDEX IF NOT EXISTS idx_project_images_position ON project_images (project_id, position);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_project_images_cover ON project_images (project_id)
+WHERE
+    is_cover;
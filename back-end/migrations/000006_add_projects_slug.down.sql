@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS project_slug_aliases;
+DROP INDEX IF EXISTS uq_projects_slug;
+
+ALTER TABLE projects
+DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,45 @@
+ALTER TABLE projects
+ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+
+-- every candidate is checked against the slugs assigned so far, a numbered suffix can
+-- collide with a name that already ends in a number ("Foo", "Foo", "Foo 2")
+DO $$
+DECLARE
+    project RECORD;
+    base TEXT;
+    candidate TEXT;
+    n INT;
+BEGIN
+    FOR project IN
+        SELECT id, name FROM projects WHERE slug IS NULL ORDER BY created_at, id
+    LOOP
+        base := COALESCE(
+            NULLIF(TRIM(BOTH '-' FROM LOWER(REGEXP_REPLACE(project.name, '[^a-zA-Z0-9]+', '-', 'g'))), ''),
+            'project'
+        );
+        candidate := base;
+        n := 1;
+
+        WHILE candidate IN ('all', 'featured')
+            OR EXISTS (SELECT 1 FROM projects WHERE slug = candidate)
+        LOOP
+            n := n + 1;
+            candidate := base || '-' || n;
+        END LOOP;
+
+        UPDATE projects SET slug = candidate WHERE id = project.id;
+    END LOOP;
+END $$;
+
+ALTER TABLE projects
+ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_projects_slug ON projects (slug);
+
+CREATE TABLE IF NOT EXISTS project_slug_aliases (
+    slug VARCHAR(255) PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW ()
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_slug_aliases ON project_slug_aliases(project_id);
@@ -0,0 +1,14 @@
+DROP VIEW IF EXISTS search_documents;
+
+DROP INDEX IF EXISTS idx_skills_search;
+DROP INDEX IF EXISTS idx_certificate_search;
+DROP INDEX IF EXISTS idx_projects_search;
+
+ALTER TABLE skills
+DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE certificate
+DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE projects
+DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,44 @@
+ALTER TABLE projects
+ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(name, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+) STORED;
+
+ALTER TABLE certificate
+ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(name, '')), 'A')
+) STORED;
+
+ALTER TABLE skills
+ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(name, '')), 'A')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_projects_search ON projects USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_certificate_search ON certificate USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_skills_search ON skills USING GIN (search_vector);
+
+CREATE OR REPLACE VIEW search_documents AS
+SELECT
+    'project'::TEXT AS type,
+    id,
+    name AS title,
+    COALESCE(NULLIF(description, ''), name) AS body,
+    search_vector
+FROM projects
+UNION ALL
+SELECT
+    'certificate'::TEXT AS type,
+    id,
+    name AS title,
+    name AS body,
+    search_vector
+FROM certificate
+UNION ALL
+SELECT
+    'skill'::TEXT AS type,
+    id,
+    name AS title,
+    name AS body,
+    search_vector
+FROM skills;
@@ -0,0 +1,41 @@
+DROP VIEW IF EXISTS search_documents;
+
+CREATE VIEW search_documents AS
+SELECT
+    'project'::TEXT AS type,
+    id,
+    name AS title,
+    COALESCE(NULLIF(description, ''), name) AS body,
+    search_vector
+FROM projects
+UNION ALL
+SELECT
+    'certificate'::TEXT AS type,
+    id,
+    name AS title,
+    name AS body,
+    search_vector
+FROM certificate
+UNION ALL
+SELECT
+    'skill'::TEXT AS type,
+    id,
+    name AS title,
+    name AS body,
+    search_vector
+FROM skills;
+
+DROP INDEX IF EXISTS idx_certificate_status;
+DROP INDEX IF EXISTS idx_projects_status;
+
+ALTER TABLE certificate
+    DROP CONSTRAINT IF EXISTS chk_certificate_status,
+    DROP COLUMN IF EXISTS preview_token,
+    DROP COLUMN IF EXISTS publish_at,
+    DROP COLUMN IF EXISTS status;
+
+ALTER TABLE projects
+    DROP CONSTRAINT IF EXISTS chk_projects_status,
+    DROP COLUMN IF EXISTS preview_token,
+    DROP COLUMN IF EXISTS publish_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,49 @@
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published',
+    ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS preview_token VARCHAR(64) UNIQUE;
+
+ALTER TABLE projects
+    ADD CONSTRAINT chk_projects_status CHECK (status IN ('draft', 'published', 'archived'));
+
+ALTER TABLE certificate
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published',
+    ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS preview_token VARCHAR(64) UNIQUE;
+
+ALTER TABLE certificate
+    ADD CONSTRAINT chk_certificate_status CHECK (status IN ('draft', 'published', 'archived'));
+
+CREATE INDEX IF NOT EXISTS idx_projects_status ON projects(status, publish_at);
+CREATE INDEX IF NOT EXISTS idx_certificate_status ON certificate(status, publish_at);
+
+CREATE OR REPLACE VIEW search_documents AS
+SELECT
+    'project'::TEXT AS type,
+    id,
+    name AS title,
+    COALESCE(NULLIF(description, ''), name) AS body,
+    search_vector,
+    status,
+    publish_at
+FROM projects
+UNION ALL
+SELECT
+    'certificate'::TEXT AS type,
+    id,
+    name AS title,
+    name AS body,
+    search_vector,
+    status,
+    publish_at
+FROM certificate
+UNION ALL
+SELECT
+    'skill'::TEXT AS type,
+    id,
+    name AS title,
+    name AS body,
+    search_vector,
+    'published'::VARCHAR(20) AS status,
+    NULL::TIMESTAMPTZ AS publish_at
+FROM skills;
@@ -0,0 +1,69 @@
+CREATE OR REPLACE VIEW search_documents AS
+SELECT
+    'project'::TEXT AS type,
+    id,
+    name AS title,
+    COALESCE(NULLIF(description, ''), name) AS body,
+    search_vector,
+    status,
+    publish_at
+FROM projects
+UNION ALL
+SELECT
+    'certificate'::TEXT AS type,
+    id,
+    name AS title,
+    name AS body,
+    search_vector,
+    status,
+    publish_at
+FROM certificate
+UNION ALL
+SELECT
+    'skill'::TEXT AS type,
+    id,
+    name AS title,
+    name AS body,
+    search_vector,
+    'published'::VARCHAR(20) AS status,
+    NULL::TIMESTAMPTZ AS publish_at
+FROM skills;
+
+DROP INDEX IF EXISTS uq_project_images_cover;
+
+DELETE FROM project_images
+WHERE
+    deleted_at IS NOT NULL;
+
+DELETE FROM projects
+WHERE
+    deleted_at IS NOT NULL;
+
+DELETE FROM certificate
+WHERE
+    deleted_at IS NOT NULL;
+
+DELETE FROM skills
+WHERE
+    deleted_at IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_project_images_cover ON project_images (project_id)
+WHERE
+    is_cover;
+
+DROP INDEX IF EXISTS idx_skills_deleted_at;
+DROP INDEX IF EXISTS idx_certificate_deleted_at;
+DROP INDEX IF EXISTS idx_project_images_deleted_at;
+DROP INDEX IF EXISTS idx_projects_deleted_at;
+
+ALTER TABLE skills
+DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE certificate
+DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE project_images
+DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE projects
+DROP COLUMN IF EXISTS deleted_at;
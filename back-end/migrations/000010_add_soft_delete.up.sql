@@ -0,0 +1,58 @@
+ALTER TABLE projects
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+ALTER TABLE project_images
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+ALTER TABLE certificate
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+ALTER TABLE skills
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_projects_deleted_at ON projects(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_project_images_deleted_at ON project_images(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_certificate_deleted_at ON certificate(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_skills_deleted_at ON skills(deleted_at);
+
+-- trashed images must not block a new cover
+DROP INDEX IF EXISTS uq_project_images_cover;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_project_images_cover ON project_images (project_id)
+WHERE
+    is_cover
+    AND deleted_at IS NULL;
+
+CREATE OR REPLACE VIEW search_documents AS
+SELECT
+    'project'::TEXT AS type,
+    id,
+    name AS title,
+    COALESCE(NULLIF(description, ''), name) AS body,
+    search_vector,
+    status,
+    publish_at
+FROM projects
+WHERE deleted_at IS NULL
+UNION ALL
+SELECT
+    'certificate'::TEXT AS type,
+    id,
+    name AS title,
+    name AS body,
+    search_vector,
+    status,
+    publish_at
+FROM certificate
+WHERE deleted_at IS NULL
+UNION ALL
+SELECT
+    'skill'::TEXT AS type,
+    id,
+    name AS title,
+    name AS body,
+    search_vector,
+    'published'::VARCHAR(20) AS status,
+    NULL::TIMESTAMPTZ AS publish_at
+FROM skills
+WHERE deleted_at IS NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_projects_featured;
+DROP INDEX IF EXISTS idx_projects_position;
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS featured,
+    DROP COLUMN IF EXISTS position;
@@ -0,0 +1,25 @@
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS featured BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- keep the current newest first order as the starting manual order
+UPDATE projects p
+SET
+    position = ordered.rn - 1
+FROM
+    (
+        SELECT
+            id,
+            ROW_NUMBER() OVER (
+                ORDER BY created_at DESC, id
+            ) AS rn
+        FROM projects
+    ) ordered
+WHERE
+    p.id = ordered.id;
+
+CREATE INDEX IF NOT EXISTS idx_projects_position ON projects(position);
+
+CREATE INDEX IF NOT EXISTS idx_projects_featured ON projects(position)
+WHERE
+    featured;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_certificate_expires_at;
+DROP INDEX IF EXISTS idx_certificate_issuer;
+
+ALTER TABLE certificate
+    DROP CONSTRAINT IF EXISTS chk_certificate_validity;
+
+ALTER TABLE certificate
+    DROP COLUMN IF EXISTS skills_covered,
+    DROP COLUMN IF EXISTS credential_id,
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS issued_at,
+    DROP COLUMN IF EXISTS issuer;
@@ -0,0 +1,16 @@
+ALTER TABLE certificate
+    ADD COLUMN IF NOT EXISTS issuer VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS issued_at DATE,
+    ADD COLUMN IF NOT EXISTS expires_at DATE,
+    ADD COLUMN IF NOT EXISTS credential_id VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS skills_covered JSONB NOT NULL DEFAULT '[]'::JSONB;
+
+ALTER TABLE certificate
+    ADD CONSTRAINT chk_certificate_validity CHECK (
+        expires_at IS NULL
+        OR issued_at IS NULL
+        OR expires_at >= issued_at
+    );
+
+CREATE INDEX IF NOT EXISTS idx_certificate_issuer ON certificate(LOWER(issuer));
+CREATE INDEX IF NOT EXISTS idx_certificate_expires_at ON certificate(expires_at);
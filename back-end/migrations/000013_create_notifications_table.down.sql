@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS certificate_reminders;
+
+DROP INDEX IF EXISTS idx_notifications_unread;
+
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,26 @@
+CREATE TABLE
+    IF NOT EXISTS notifications (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        type VARCHAR(50) NOT NULL,
+        title VARCHAR(255) NOT NULL,
+        body TEXT NOT NULL DEFAULT '',
+        entity_type VARCHAR(50),
+        entity_id UUID,
+        read_at TIMESTAMPTZ,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW ()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(created_at DESC)
+WHERE
+    read_at IS NULL;
+
+-- one row per reminder sent, a renewed certificate gets a new expires_at and is reminded again
+CREATE TABLE
+    IF NOT EXISTS certificate_reminders (
+        certificate_id UUID NOT NULL REFERENCES certificate(id) ON DELETE CASCADE,
+        lead_days INT NOT NULL,
+        expires_at DATE NOT NULL,
+        sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        PRIMARY KEY (certificate_id, lead_days, expires_at)
+    );
@@ -0,0 +1,2 @@
+ALTER TABLE certificate
+    DROP COLUMN IF EXISTS pdf_url;
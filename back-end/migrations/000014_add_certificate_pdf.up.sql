@@ -0,0 +1,2 @@
+ALTER TABLE certificate
+    ADD COLUMN IF NOT EXISTS pdf_url VARCHAR(255);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_link_checks_broken;
+
+DROP TABLE IF EXISTS link_checks;
@@ -0,0 +1,17 @@
+CREATE TABLE
+    IF NOT EXISTS link_checks (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        entity_type VARCHAR(50) NOT NULL,
+        entity_id UUID NOT NULL,
+        url VARCHAR(255) NOT NULL,
+        status_code INT,
+        error TEXT,
+        redirect_url TEXT,
+        broken BOOLEAN NOT NULL DEFAULT FALSE,
+        checked_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        UNIQUE (entity_type, entity_id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_link_checks_broken ON link_checks(checked_at DESC)
+WHERE
+    broken;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_skills_category;
+
+ALTER TABLE skills
+DROP COLUMN IF EXISTS category_id;
+
+DROP TABLE IF EXISTS skill_categories;
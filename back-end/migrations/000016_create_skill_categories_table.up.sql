@@ -0,0 +1,36 @@
+CREATE TABLE
+    IF NOT EXISTS skill_categories (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        name VARCHAR(100) NOT NULL UNIQUE,
+        position INT NOT NULL DEFAULT 0,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW ()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_skill_categories_position ON skill_categories(position);
+
+-- existing skills start in a single category the owner can split up afterwards
+INSERT INTO
+    skill_categories (name, position)
+VALUES
+    ('General', 0)
+ON CONFLICT (name) DO NOTHING;
+
+-- a category cannot be deleted while skills, trashed ones included, still use it
+ALTER TABLE skills
+ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES skill_categories(id) ON DELETE RESTRICT;
+
+UPDATE skills
+SET
+    category_id = (
+        SELECT id
+        FROM skill_categories
+        WHERE name = 'General'
+    )
+WHERE
+    category_id IS NULL;
+
+ALTER TABLE skills
+ALTER COLUMN category_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_skills_category ON skills(category_id);
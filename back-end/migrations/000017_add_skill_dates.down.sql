@@ -0,0 +1,17 @@
+ALTER TABLE skills
+ADD COLUMN IF NOT EXISTS experience INT NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS period VARCHAR;
+
+UPDATE skills
+SET
+    experience = (
+        EXTRACT(YEAR FROM AGE(COALESCE(ended_at, CURRENT_DATE), started_at)) * 12
+        + EXTRACT(MONTH FROM AGE(COALESCE(ended_at, CURRENT_DATE), started_at))
+    )::INT,
+    period = 'MONTHS';
+
+ALTER TABLE skills
+ALTER COLUMN experience DROP DEFAULT,
+DROP CONSTRAINT IF EXISTS chk_skills_dates,
+DROP COLUMN IF EXISTS ended_at,
+DROP COLUMN IF EXISTS started_at;
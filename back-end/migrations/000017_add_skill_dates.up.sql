@@ -0,0 +1,26 @@
+ALTER TABLE skills
+ADD COLUMN IF NOT EXISTS started_at DATE,
+ADD COLUMN IF NOT EXISTS ended_at DATE;
+
+-- the stored experience was true when the row was last edited, count back from there
+UPDATE skills
+SET
+    started_at = (
+        updated_at - CASE
+            WHEN UPPER(period) = 'YEARS' THEN MAKE_INTERVAL(years => experience)
+            ELSE MAKE_INTERVAL(months => experience)
+        END
+    )::DATE
+WHERE
+    started_at IS NULL;
+
+ALTER TABLE skills
+ALTER COLUMN started_at SET NOT NULL,
+ADD CONSTRAINT chk_skills_dates CHECK (
+    ended_at IS NULL
+    OR ended_at >= started_at
+);
+
+ALTER TABLE skills
+DROP COLUMN IF EXISTS experience,
+DROP COLUMN IF EXISTS period;
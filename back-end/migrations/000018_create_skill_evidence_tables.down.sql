@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS certificate_skills;
+
+DROP TABLE IF EXISTS project_skills;
@@ -0,0 +1,19 @@
+CREATE TABLE
+    IF NOT EXISTS project_skills (
+        project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+        skill_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        PRIMARY KEY (project_id, skill_id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_project_skills_skill ON project_skills(skill_id);
+
+CREATE TABLE
+    IF NOT EXISTS certificate_skills (
+        certificate_id UUID NOT NULL REFERENCES certificate(id) ON DELETE CASCADE,
+        skill_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        PRIMARY KEY (certificate_id, skill_id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_certificate_skills_skill ON certificate_skills(skill_id);
@@ -0,0 +1,8 @@
+CREATE TABLE
+    IF NOT EXISTS owner_avatars (
+        owner_id UUID NOT NULL REFERENCES owner(id) ON DELETE CASCADE,
+        size INT NOT NULL CHECK (size > 0),
+        url TEXT NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        PRIMARY KEY (owner_id, size)
+    );
@@ -0,0 +1,12 @@
+CREATE TABLE
+    IF NOT EXISTS password_reset_tokens (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        owner_id UUID NOT NULL REFERENCES owner(id) ON DELETE CASCADE,
+        -- sha256 of the token, the token itself is only ever in the email
+        token_hash CHAR(64) NOT NULL UNIQUE,
+        expires_at TIMESTAMPTZ NOT NULL,
+        used_at TIMESTAMPTZ,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW ()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_owner ON password_reset_tokens(owner_id);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS login_challenges;
+
+DROP TABLE IF EXISTS owner_recovery_codes;
+
+ALTER TABLE owner
+    DROP COLUMN IF EXISTS totp_last_counter,
+    DROP COLUMN IF EXISTS totp_enabled_at,
+    DROP COLUMN IF EXISTS totp_secret;
@@ -0,0 +1,30 @@
+ALTER TABLE owner
+    -- set while enrolling, two factor is only on once totp_enabled_at is set
+    ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMPTZ,
+    -- last accepted time step, a code is never accepted twice
+    ADD COLUMN IF NOT EXISTS totp_last_counter BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE
+    IF NOT EXISTS owner_recovery_codes (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        owner_id UUID NOT NULL REFERENCES owner(id) ON DELETE CASCADE,
+        code_hash CHAR(64) NOT NULL UNIQUE,
+        used_at TIMESTAMPTZ,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW ()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_owner_recovery_codes_owner ON owner_recovery_codes(owner_id);
+
+-- a password login of a two factor account waits here for the code
+CREATE TABLE
+    IF NOT EXISTS login_challenges (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        owner_id UUID NOT NULL REFERENCES owner(id) ON DELETE CASCADE,
+        token_hash CHAR(64) NOT NULL UNIQUE,
+        attempts INT NOT NULL DEFAULT 0,
+        expires_at TIMESTAMPTZ NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW ()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_login_challenges_owner ON login_challenges(owner_id);
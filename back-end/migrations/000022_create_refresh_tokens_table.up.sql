@@ -0,0 +1,16 @@
+CREATE TABLE
+    IF NOT EXISTS refresh_tokens (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        owner_id UUID NOT NULL REFERENCES owner(id) ON DELETE CASCADE,
+        -- every token rotated out of the same login shares the family
+        family_id UUID NOT NULL,
+        token_hash CHAR(64) NOT NULL UNIQUE,
+        expires_at TIMESTAMPTZ NOT NULL,
+        -- set once the token was exchanged, using it again revokes the family
+        used_at TIMESTAMPTZ,
+        revoked_at TIMESTAMPTZ,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW ()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_owner ON refresh_tokens(owner_id);
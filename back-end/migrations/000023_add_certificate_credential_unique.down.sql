@@ -0,0 +1 @@
+DROP INDEX IF EXISTS uq_certificate_credential_id;
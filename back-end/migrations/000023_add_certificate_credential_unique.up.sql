@@ -0,0 +1,20 @@
+-- a credential can only be imported once, the check in the import alone races with a
+-- concurrent import. Rows added by hand before this could share an id, the oldest keeps it.
+UPDATE certificate c
+SET
+    credential_id = NULL
+WHERE
+    c.deleted_at IS NULL
+    AND c.credential_id IS NOT NULL
+    AND EXISTS (
+        SELECT 1
+        FROM certificate o
+        WHERE
+            o.deleted_at IS NULL
+            AND o.credential_id = c.credential_id
+            AND (o.created_at, o.id) < (c.created_at, c.id)
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_certificate_credential_id ON certificate(credential_id)
+WHERE
+    deleted_at IS NULL;
@@ -8,17 +8,31 @@ import (
 
 	"github.com/devanadindra/portfolio/back-end/database"
 	"github.com/devanadindra/portfolio/back-end/utils/logger"
+	"github.com/devanadindra/portfolio/back-end/utils/scheduler"
 )
 
 type Dependency struct {
 	handler    *gin.Engine
 	OwnerDB    *database.OwnerDB
 	VisitorsDB *database.VisitorsDB
+	scheduler  *scheduler.Scheduler
+}
+
+// StartJobs runs the background jobs until ctx is done or Close is called.
+func (d *Dependency) StartJobs(ctx context.Context) {
+	if d.scheduler != nil {
+		d.scheduler.Start(ctx)
+	}
 }
 
 func (d *Dependency) Close() {
 	ctx := context.Background()
 
+	// jobs must finish before the databases they use are closed
+	if d.scheduler != nil {
+		d.scheduler.Stop()
+	}
+
 	if d.VisitorsDB != nil {
 		if db, err := d.VisitorsDB.DB.DB(); err == nil {
 			_ = db.Close()
@@ -7,14 +7,20 @@ import (
 
 	"github.com/devanadindra/portfolio/back-end/database"
 	"github.com/devanadindra/portfolio/back-end/domains/certif"
+	"github.com/devanadindra/portfolio/back-end/domains/linkcheck"
+	"github.com/devanadindra/portfolio/back-end/domains/notification"
 	"github.com/devanadindra/portfolio/back-end/domains/project"
+	"github.com/devanadindra/portfolio/back-end/domains/search"
 	"github.com/devanadindra/portfolio/back-end/domains/skill"
+	"github.com/devanadindra/portfolio/back-end/domains/tag"
+	"github.com/devanadindra/portfolio/back-end/domains/trash"
 	"github.com/devanadindra/portfolio/back-end/domains/user"
 	"github.com/devanadindra/portfolio/back-end/middlewares"
 	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
 	"github.com/devanadindra/portfolio/back-end/utils/config"
 	"github.com/devanadindra/portfolio/back-end/utils/constants"
 	"github.com/devanadindra/portfolio/back-end/utils/respond"
+	"github.com/devanadindra/portfolio/back-end/utils/scheduler"
 )
 
 func NewDependency(
@@ -26,6 +32,12 @@ func NewDependency(
 	projectHandler project.Handler,
 	skillHandler skill.Handler,
 	certifHandler certif.Handler,
+	searchHandler search.Handler,
+	tagHandler tag.Handler,
+	trashHandler trash.Handler,
+	notificationHandler notification.Handler,
+	linkCheckHandler linkcheck.Handler,
+	jobScheduler *scheduler.Scheduler,
 ) *Dependency {
 
 	if conf.Environment != config.DEVELOPMENT_ENVIRONMENT {
@@ -48,17 +60,27 @@ func NewDependency(
 	api.Static("/uploads", "./uploads")
 	api.Static("/kamus_videos", "./kamus_videos")
 	api.GET("/health-check", HealthCheck)
+	api.GET("/search", mw.OptionalJWT(constants.OWNER), searchHandler.Search)
 
 	// domain user
 	user := api.Group("/user")
 	{
 		user.POST("/login", mw.BasicAuth, userHandler.Login)
+		user.POST("/login/2fa", mw.BasicAuth, userHandler.LoginTwoFactor)
+		user.POST("/refresh", mw.BasicAuth, userHandler.Refresh)
 		user.GET("/verify-token", mw.JWT(constants.OWNER), userHandler.VerifyToken)
-		user.POST("/logout", mw.JWT(constants.OWNER), userHandler.Logout)
+		user.POST("/logout", mw.OptionalJWT(constants.OWNER), userHandler.Logout)
 		user.POST("/reset-req", mw.BasicAuth, userHandler.ResetPassword)
 		user.PATCH("/reset-submit", mw.BasicAuth, userHandler.ResetPasswordSubmit)
 		user.PATCH("/password", mw.JWT(constants.OWNER), userHandler.ChangePassword)
+		user.PUT("/avatar", mw.JWT(constants.OWNER), userHandler.AddAvatar)
+		user.GET("/2fa", mw.JWT(constants.OWNER), userHandler.GetTwoFactorStatus)
+		user.POST("/2fa/enroll", mw.JWT(constants.OWNER), userHandler.EnrollTwoFactor)
+		user.POST("/2fa/confirm", mw.JWT(constants.OWNER), userHandler.ConfirmTwoFactor)
+		user.POST("/2fa/disable", mw.JWT(constants.OWNER), userHandler.DisableTwoFactor)
+		user.POST("/2fa/recovery-codes", mw.JWT(constants.OWNER), userHandler.RegenerateRecoveryCodes)
 		user.GET("/about", mw.OptionalJWT(constants.OWNER), userHandler.GetAbout)
+		user.PATCH("/about", mw.JWT(constants.OWNER), userHandler.UpdateAbout)
 		user.GET("/check-jwt", mw.JWT(constants.OWNER), func(ctx *gin.Context) {
 			respond.Success(ctx, http.StatusOK, "JWT is valid")
 		})
@@ -68,18 +90,78 @@ func NewDependency(
 	{
 		project.GET("/", mw.JWT(constants.OWNER), projectHandler.GetProjects)
 		project.GET("/all", mw.OptionalJWT(constants.OWNER), projectHandler.GetAllProjects)
+		project.GET("/featured", mw.OptionalJWT(constants.OWNER), projectHandler.GetFeaturedProjects)
+		project.GET("/:idOrSlug", mw.OptionalJWT(constants.OWNER), projectHandler.GetProjectById)
+		project.POST("/", mw.JWT(constants.OWNER), projectHandler.CreateProject)
+		project.PUT("/order", mw.JWT(constants.OWNER), projectHandler.ReorderProjects)
+		project.PATCH("/:id", mw.JWT(constants.OWNER), projectHandler.UpdateProject)
+		project.POST("/:id/images", mw.JWT(constants.OWNER), projectHandler.AddProjectImages)
+		project.PUT("/:id/images/order", mw.JWT(constants.OWNER), projectHandler.ReorderProjectImages)
+		project.PATCH("/:id/images/:imageId/cover", mw.JWT(constants.OWNER), projectHandler.SetProjectCover)
+		project.DELETE("/:id/images/:imageId", mw.JWT(constants.OWNER), projectHandler.DeleteProjectImage)
 		project.DELETE("/:id", mw.JWT(constants.OWNER), projectHandler.DeleteProject)
+		project.PUT("/:id/tags", mw.JWT(constants.OWNER), projectHandler.SetProjectTags)
+		project.PATCH("/:id/status", mw.JWT(constants.OWNER), projectHandler.UpdateProjectStatus)
+		project.PATCH("/:id/featured", mw.JWT(constants.OWNER), projectHandler.SetProjectFeatured)
+		project.POST("/:id/preview-token", mw.JWT(constants.OWNER), projectHandler.CreatePreviewToken)
+		project.DELETE("/:id/preview-token", mw.JWT(constants.OWNER), projectHandler.DeletePreviewToken)
 	}
 
-	// Skill := api.Group("/skill")
-	// {
-	// }
+	tag := api.Group("/tag")
+	{
+		tag.GET("/", mw.OptionalJWT(constants.OWNER), tagHandler.GetAllTags)
+		tag.POST("/", mw.JWT(constants.OWNER), tagHandler.CreateTag)
+		tag.PATCH("/:id", mw.JWT(constants.OWNER), tagHandler.UpdateTag)
+		tag.DELETE("/:id", mw.JWT(constants.OWNER), tagHandler.DeleteTag)
+	}
+
+	skill := api.Group("/skill")
+	{
+		skill.GET("/", mw.OptionalJWT(constants.OWNER), skillHandler.GetAllSkill)
+		skill.GET("/:id", mw.OptionalJWT(constants.OWNER), skillHandler.GetSkillById)
+		skill.POST("/", mw.JWT(constants.OWNER), skillHandler.CreateSkill)
+		skill.PATCH("/:id", mw.JWT(constants.OWNER), skillHandler.UpdateSkill)
+		skill.DELETE("/:id", mw.JWT(constants.OWNER), skillHandler.DeleteSkill)
+		skill.POST("/:id/projects/:projectId", mw.JWT(constants.OWNER), skillHandler.AttachProject)
+		skill.DELETE("/:id/projects/:projectId", mw.JWT(constants.OWNER), skillHandler.DetachProject)
+		skill.POST("/:id/certificates/:certifId", mw.JWT(constants.OWNER), skillHandler.AttachCertificate)
+		skill.DELETE("/:id/certificates/:certifId", mw.JWT(constants.OWNER), skillHandler.DetachCertificate)
+		skill.GET("/category", mw.OptionalJWT(constants.OWNER), skillHandler.GetSkillCategories)
+		skill.POST("/category", mw.JWT(constants.OWNER), skillHandler.CreateSkillCategory)
+		skill.PUT("/category/order", mw.JWT(constants.OWNER), skillHandler.ReorderSkillCategories)
+		skill.PATCH("/category/:id", mw.JWT(constants.OWNER), skillHandler.UpdateSkillCategory)
+		skill.DELETE("/category/:id", mw.JWT(constants.OWNER), skillHandler.DeleteSkillCategory)
+		skill.POST("/category/:id/skills", mw.JWT(constants.OWNER), skillHandler.MoveSkills)
+	}
 
 	certif := api.Group("/certif")
 	{
 		certif.GET("/", mw.OptionalJWT(constants.OWNER), certifHandler.GetAllCertif)
-		certif.GET("/:id", mw.JWT(constants.OWNER), certifHandler.GetCertifById)
+		certif.GET("/:id", mw.OptionalJWT(constants.OWNER), certifHandler.GetCertifById)
+		certif.POST("/", mw.JWT(constants.OWNER), certifHandler.CreateCertif)
+		certif.POST("/import", mw.JWT(constants.OWNER), certifHandler.ImportCertif)
+		certif.PATCH("/:id", mw.JWT(constants.OWNER), certifHandler.UpdateCertif)
 		certif.DELETE("/:id", mw.JWT(constants.OWNER), certifHandler.DeleteCertif)
+		certif.PATCH("/:id/status", mw.JWT(constants.OWNER), certifHandler.UpdateCertifStatus)
+		certif.POST("/:id/preview-token", mw.JWT(constants.OWNER), certifHandler.CreatePreviewToken)
+		certif.DELETE("/:id/preview-token", mw.JWT(constants.OWNER), certifHandler.DeletePreviewToken)
+	}
+
+	trash := api.Group("/trash")
+	{
+		trash.GET("/", mw.JWT(constants.OWNER), trashHandler.GetTrash)
+		trash.POST("/:type/:id/restore", mw.JWT(constants.OWNER), trashHandler.Restore)
+	}
+
+	notification := api.Group("/notification")
+	{
+		notification.GET("/", mw.JWT(constants.OWNER), notificationHandler.GetNotifications)
+		notification.PATCH("/:id/read", mw.JWT(constants.OWNER), notificationHandler.MarkAsRead)
+	}
+
+	linkCheck := api.Group("/link-check")
+	{
+		linkCheck.GET("/broken", mw.JWT(constants.OWNER), linkCheckHandler.GetBrokenLinks)
 	}
 
 	router.NoRoute(func(ctx *gin.Context) {
@@ -94,6 +176,7 @@ func NewDependency(
 		handler:    router,
 		OwnerDB:    OwnerDB,
 		VisitorsDB: VisitorsDB,
+		scheduler:  jobScheduler,
 	}
 }
 
@@ -71,6 +71,24 @@ func msgForTag(kind reflect.Kind, tag, param string) string {
 		return "This field cannot be empty"
 	case "number":
 		return "Please enter a valid number"
+	case "max":
+		switch kind {
+		case reflect.String:
+			return fmt.Sprintf("Please use at most %s characters", param)
+		case reflect.Array, reflect.Slice:
+			return fmt.Sprintf("Please add at most %s item(s)", param)
+		default:
+			return fmt.Sprintf("The value must be at most %s", param)
+		}
+	case "min":
+		switch kind {
+		case reflect.String:
+			return fmt.Sprintf("Please use at least %s characters", param)
+		case reflect.Array, reflect.Slice:
+			return fmt.Sprintf("Please add at least %s item(s)", param)
+		default:
+			return fmt.Sprintf("The value must be at least %s", param)
+		}
 	case "gt":
 		switch kind {
 		case reflect.Array, reflect.Slice:
@@ -117,8 +117,8 @@ func LatihanNotFound(latihanId string) error {
 	return NewWarn(http.StatusConflict, fmt.Sprintf("latihan dengan id '%s' tidak ditemukan", latihanId))
 }
 
-func ProjectNotFound(projectId string) error {
-	return NewWarn(http.StatusConflict, fmt.Sprintf("kamus dengan projectId '%s' tidak ditemukan", projectId))
+func ProjectNotFound(idOrSlug string) error {
+	return NewWarn(http.StatusNotFound, fmt.Sprintf("project '%s' not found", idOrSlug))
 }
 
 func ExistingStatsLatihan() error {
@@ -131,3 +131,211 @@ func ErrGoogleEmailLocked() error {
 		"Email tidak dapat diubah untuk akun yang login via Google",
 	)
 }
+
+func InvalidProjectId() error {
+	return NewWarn(http.StatusBadRequest, "projectId must be UUID!")
+}
+
+func InvalidImageFile(filename string) error {
+	return NewWarn(http.StatusBadRequest, fmt.Sprintf("file '%s' must be an image (jpg, jpeg, png, gif, bmp)", filename))
+}
+
+func InvalidProjectImageId() error {
+	return NewWarn(http.StatusBadRequest, "imageId must be UUID!")
+}
+
+func ProjectImageNotFound() error {
+	return NewWarn(http.StatusNotFound, "Project image not found!")
+}
+
+func InvalidImageOrder() error {
+	return NewWarn(http.StatusBadRequest, "image_ids must contain every image of the project exactly once")
+}
+
+func InvalidProjectOrder() error {
+	return NewWarn(http.StatusBadRequest, "project_ids must contain every project exactly once")
+}
+
+func InvalidTagId() error {
+	return NewWarn(http.StatusBadRequest, "tagId must be UUID!")
+}
+
+func InvalidTagName(name string) error {
+	return NewWarn(http.StatusBadRequest, fmt.Sprintf("tag name '%s' must contain at least one letter or number", name))
+}
+
+func TagNotFound(tagId string) error {
+	return NewWarn(http.StatusNotFound, fmt.Sprintf("tag '%s' not found", tagId))
+}
+
+func DuplicateTag(slug string) error {
+	return NewWarn(http.StatusConflict, fmt.Sprintf("tag '%s' already exists", slug))
+}
+
+func SkillNotFound(skillId string) error {
+	return NewWarn(http.StatusNotFound, fmt.Sprintf("skill '%s' not found", skillId))
+}
+
+func InvalidCertifId() error {
+	return NewWarn(http.StatusBadRequest, "certifId must be UUID!")
+}
+
+func InvalidPdfFile(filename string) error {
+	return NewWarn(http.StatusUnprocessableEntity, fmt.Sprintf("the first page of '%s' could not be rendered, make sure it is a valid PDF", filename))
+}
+
+func InvalidCertifFile() error {
+	return NewWarn(http.StatusBadRequest, "send either an image or a pdf, not both")
+}
+
+func InvalidCertifValidity() error {
+	return NewWarn(http.StatusBadRequest, "expires_at must not be before issued_at")
+}
+
+func CertifNotFound(certifId string) error {
+	return NewWarn(http.StatusNotFound, fmt.Sprintf("certificate '%s' not found", certifId))
+}
+
+func InvalidNotificationId() error {
+	return NewWarn(http.StatusBadRequest, "notificationId must be UUID!")
+}
+
+func NotificationNotFound(notificationId string) error {
+	return NewWarn(http.StatusNotFound, fmt.Sprintf("notification '%s' not found", notificationId))
+}
+
+func InvalidTrashId() error {
+	return NewWarn(http.StatusBadRequest, "id must be UUID!")
+}
+
+func InvalidTrashType(itemType string) error {
+	return NewWarn(http.StatusBadRequest, fmt.Sprintf("'%s' is not a valid trash type (project, project_image, certificate, skill)", itemType))
+}
+
+func TrashItemNotFound(itemType string, id string) error {
+	return NewWarn(http.StatusNotFound, fmt.Sprintf("%s '%s' not found in trash", itemType, id))
+}
+
+func TrashParentDeleted(parentType string) error {
+	return NewWarn(http.StatusConflict, fmt.Sprintf("the %s of this item is in the trash, restore it first", parentType))
+}
+
+func InvalidBadgeInput() error {
+	return NewWarn(http.StatusBadRequest, "send either a badge file or an assertion, not both")
+}
+
+func InvalidBadgeFile(filename string) error {
+	return NewWarn(http.StatusUnprocessableEntity, fmt.Sprintf("'%s' is not a baked PNG or SVG badge nor an assertion JSON", filename))
+}
+
+func BadgeTooLarge(maxSize int64) error {
+	return NewWarn(http.StatusRequestEntityTooLarge, fmt.Sprintf("badge must not be larger than %d bytes", maxSize))
+}
+
+func BadgeAssertionNotFound(filename string) error {
+	return NewWarn(http.StatusUnprocessableEntity, fmt.Sprintf("no open badge assertion is baked into '%s'", filename))
+}
+
+func InvalidBadgeAssertion(reason string) error {
+	return NewWarn(http.StatusUnprocessableEntity, fmt.Sprintf("invalid open badge assertion: %s", reason))
+}
+
+func BadgeFetchFailed(url string) error {
+	return NewWarn(http.StatusBadGateway, fmt.Sprintf("could not fetch '%s'", url))
+}
+
+func BadgeRevoked() error {
+	return NewWarn(http.StatusUnprocessableEntity, "the badge has been revoked by its issuer")
+}
+
+func CertifAlreadyImported(certifId string) error {
+	return NewWarn(http.StatusConflict, fmt.Sprintf("this badge was already imported as certificate '%s'", certifId))
+}
+
+func InvalidSkillId() error {
+	return NewWarn(http.StatusBadRequest, "skillId must be UUID!")
+}
+
+func InvalidIconFile(filename string) error {
+	return NewWarn(http.StatusBadRequest, fmt.Sprintf("file '%s' must be an icon (svg, png, jpg, jpeg, gif)", filename))
+}
+
+func AboutNotFound() error {
+	return NewWarn(http.StatusNotFound, "about page has not been set up yet")
+}
+
+func InvalidSkillCategoryId() error {
+	return NewWarn(http.StatusBadRequest, "categoryId must be UUID!")
+}
+
+func SkillCategoryNotFound(categoryId string) error {
+	return NewWarn(http.StatusNotFound, fmt.Sprintf("skill category '%s' not found", categoryId))
+}
+
+func DuplicateSkillCategory(name string) error {
+	return NewWarn(http.StatusConflict, fmt.Sprintf("skill category '%s' already exists", name))
+}
+
+func SkillCategoryNotEmpty(count int64) error {
+	return NewWarn(http.StatusConflict, fmt.Sprintf("skill category still has %d skill(s), trashed ones included, move them to another category first", count))
+}
+
+func InvalidSkillCategoryOrder() error {
+	return NewWarn(http.StatusBadRequest, "category_ids must contain every skill category exactly once")
+}
+
+func InvalidSkillDates(reason string) error {
+	return NewWarn(http.StatusBadRequest, reason)
+}
+
+func InvalidAboutName() error {
+	return NewWarn(http.StatusBadRequest, "Name: This field cannot be empty")
+}
+
+func InvalidAvatarFile(filename string) error {
+	return NewWarn(http.StatusUnprocessableEntity, fmt.Sprintf("'%s' is not a PNG, JPEG or GIF image", filename))
+}
+
+func AvatarTooLarge(maxSize int64) error {
+	return NewWarn(http.StatusRequestEntityTooLarge, fmt.Sprintf("avatar must not be larger than %d bytes", maxSize))
+}
+
+func AvatarDimensionsTooLarge(maxPixels int) error {
+	return NewWarn(http.StatusRequestEntityTooLarge, fmt.Sprintf("avatar must not have more than %d pixels", maxPixels))
+}
+
+func InvalidResetToken() error {
+	return NewWarn(http.StatusBadRequest, "the reset link is invalid or has expired, please request a new one")
+}
+
+func TwoFactorAlreadyEnabled() error {
+	return NewWarn(http.StatusConflict, "two factor authentication is already enabled, disable it first")
+}
+
+func TwoFactorNotEnrolled() error {
+	return NewWarn(http.StatusBadRequest, "start the two factor enrollment first")
+}
+
+func TwoFactorNotEnabled() error {
+	return NewWarn(http.StatusBadRequest, "two factor authentication is not enabled")
+}
+
+func InvalidTwoFactorCode() error {
+	return NewWarn(http.StatusUnauthorized, "the code is invalid or was already used")
+}
+
+func InvalidLoginChallenge() error {
+	return NewWarn(http.StatusUnauthorized, "the login has expired, please sign in again")
+}
+
+func InvalidRefreshToken() error {
+	return NewWarn(http.StatusUnauthorized, "the session has expired, please sign in again")
+}
+
+func CertifCredentialIdTaken() error {
+	return NewWarn(http.StatusConflict, "another certificate already uses this credential id")
+}
+
+func TooManyLoginChallenges() error {
+	return NewWarn(http.StatusTooManyRequests, "too many sign ins are waiting for a two factor code, please try again later")
+}
@@ -1,6 +1,10 @@
 package common
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"slices"
@@ -10,6 +14,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgconn"
 
 	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
 	"github.com/devanadindra/portfolio/back-end/utils/constants"
@@ -149,3 +154,56 @@ func ToArrayAny(a []string) []any {
 	}
 	return res
 }
+
+const maxSlugLength = 200
+
+// Slugify lowercases s and collapses every run of non alphanumeric characters into a single dash.
+// It returns an empty string when s has no alphanumeric characters.
+func Slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimRight(b.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+
+	return slug
+}
+
+// IsPublished reports whether content with this status and schedule is visible to visitors.
+func IsPublished(status string, publishAt *time.Time) bool {
+	return status == constants.STATUS_PUBLISHED && (publishAt == nil || !publishAt.After(time.Now()))
+}
+
+// GenerateToken returns a random url safe token built from size random bytes.
+func GenerateToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// TokenEquals compares two secrets in constant time.
+func TokenEquals(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// IsUniqueViolation reports whether err was raised by the unique index or constraint named
+// constraint, so a race on it can be answered like the check done before the write.
+func IsUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
@@ -0,0 +1,141 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"lower case", "Portfolio Site", "portfolio-site"},
+		{"symbols collapse", "Go -- REST / API!", "go-rest-api"},
+		{"trims edges", "  --Hello World--  ", "hello-world"},
+		{"keeps numbers", "Foo 2", "foo-2"},
+		{"non ascii dropped", "Café Über", "caf-ber"},
+		{"nothing left", "!!!", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.in); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugifyTruncates(t *testing.T) {
+	got := Slugify(strings.Repeat("a", maxSlugLength-1) + " bcd")
+	if len(got) > maxSlugLength {
+		t.Fatalf("len = %d, want at most %d", len(got), maxSlugLength)
+	}
+	if strings.HasSuffix(got, "-") {
+		t.Errorf("Slugify kept a trailing dash: %q", got[len(got)-5:])
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	violation := &pgconn.PgError{Code: "23505", ConstraintName: "uq_a"}
+
+	if !IsUniqueViolation(fmt.Errorf("insert: %w", violation), "uq_a") {
+		t.Error("wrapped violation of uq_a not detected")
+	}
+	if IsUniqueViolation(violation, "uq_b") {
+		t.Error("violation of uq_a reported for uq_b")
+	}
+	if IsUniqueViolation(&pgconn.PgError{Code: "23503", ConstraintName: "uq_a"}, "uq_a") {
+		t.Error("foreign key violation reported as unique violation")
+	}
+	if IsUniqueViolation(errors.New("23505"), "uq_a") {
+		t.Error("plain error reported as unique violation")
+	}
+}
+
+func TestIsPublished(t *testing.T) {
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		status    string
+		publishAt *time.Time
+		want      bool
+	}{
+		{"published", constants.STATUS_PUBLISHED, nil, true},
+		{"schedule passed", constants.STATUS_PUBLISHED, &past, true},
+		{"scheduled", constants.STATUS_PUBLISHED, &future, false},
+		{"draft", constants.STATUS_DRAFT, nil, false},
+		{"archived", constants.STATUS_ARCHIVED, &past, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsPublished(tt.status, tt.publishAt); got != tt.want {
+			t.Errorf("%s: IsPublished() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateToken(t *testing.T) {
+	a, err := GenerateToken(16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := GenerateToken(16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(a) != 32 || a == b {
+		t.Errorf("GenerateToken() = %q, %q", a, b)
+	}
+	if !TokenEquals(a, a) || TokenEquals(a, b) || TokenEquals(a, "") {
+		t.Error("TokenEquals() compared wrong")
+	}
+}
+
+func TestGetMetaDataOrderBy(t *testing.T) {
+	tests := []struct {
+		query   string
+		orderBy string
+		status  int
+	}{
+		{query: "", orderBy: "created_at"},
+		{query: "?order-by=position", orderBy: "position"},
+		{query: "?order-by=name", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/api/project"+tt.query, nil)
+
+		res, err := GetMetaData(ctx, validator.New(), "created_at", "position")
+		if tt.status != 0 {
+			if got := apierror.GetApiErrors(err).Code; got != tt.status {
+				t.Errorf("%q: status = %d (%v), want %d", tt.query, got, err, tt.status)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: GetMetaData() error = %v", tt.query, err)
+		}
+		if res.OrderBy != tt.orderBy {
+			t.Errorf("%q: order by = %s, want %s", tt.query, res.OrderBy, tt.orderBy)
+		}
+	}
+}
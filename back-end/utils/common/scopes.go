@@ -0,0 +1,64 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// FilterScope applies the keyword and created/updated date range filters parsed by GetMetaData.
+// The keyword is matched case-insensitively against searchColumns, date ranges are inclusive.
+func FilterScope(filter constants.FilterReq, searchColumns ...string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		keyword := strings.TrimSpace(filter.Keyword)
+		if keyword != "" && len(searchColumns) > 0 {
+			pattern := "%" + likeEscaper.Replace(keyword) + "%"
+
+			conditions := make([]clause.Expression, len(searchColumns))
+			for i, column := range searchColumns {
+				conditions[i] = clause.Expr{
+					SQL:  "? ILIKE ?",
+					Vars: []any{clause.Column{Table: clause.CurrentTable, Name: column}, pattern},
+				}
+			}
+			db = db.Where(clause.Or(conditions...))
+		}
+
+		createdAt := clause.Column{Table: clause.CurrentTable, Name: "created_at"}
+		updatedAt := clause.Column{Table: clause.CurrentTable, Name: "updated_at"}
+
+		if filter.StartCreatedAt != nil {
+			db = db.Where(clause.Gte{Column: createdAt, Value: *filter.StartCreatedAt})
+		}
+		if filter.EndCreatedAt != nil {
+			db = db.Where(clause.Lte{Column: createdAt, Value: *filter.EndCreatedAt})
+		}
+		if filter.StartUpdatedAt != nil {
+			db = db.Where(clause.Gte{Column: updatedAt, Value: *filter.StartUpdatedAt})
+		}
+		if filter.EndUpdatedAt != nil {
+			db = db.Where(clause.Lte{Column: updatedAt, Value: *filter.EndUpdatedAt})
+		}
+
+		return db
+	}
+}
+
+// PaginateScope applies the order, limit and offset parsed by GetMetaData.
+// OrderBy is already checked against the allowed columns so it is safe to format into SQL.
+func PaginateScope(filter constants.FilterReq) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		offset := (filter.Page - 1) * filter.Limit
+
+		return db.
+			Order(fmt.Sprintf("%s %s", filter.OrderBy, filter.SortOrder)).
+			Limit(int(filter.Limit)).
+			Offset(int(offset))
+	}
+}
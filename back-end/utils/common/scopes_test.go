@@ -0,0 +1,79 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+type scopedRow struct {
+	ID string
+}
+
+func (scopedRow) TableName() string {
+	return "rows"
+}
+
+func scopeSQL(t *testing.T, scope func(db *gorm.DB) *gorm.DB) string {
+	t.Helper()
+
+	db, _ := testutil.NewMockDB(t)
+	return db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Scopes(scope).Find(&[]scopedRow{})
+	})
+}
+
+func TestFilterScope(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		filter  constants.FilterReq
+		columns []string
+		want    string
+	}{
+		{name: "nothing to filter", want: `SELECT * FROM "rows"`},
+		{name: "blank keyword", filter: constants.FilterReq{Keyword: "   "}, columns: []string{"name"}, want: `SELECT * FROM "rows"`},
+		{name: "keyword without columns", filter: constants.FilterReq{Keyword: "go"}, want: `SELECT * FROM "rows"`},
+		{
+			name:    "keyword",
+			filter:  constants.FilterReq{Keyword: " Go "},
+			columns: []string{"name", "description"},
+			want:    `SELECT * FROM "rows" WHERE ("rows"."name" ILIKE '%Go%' OR "rows"."description" ILIKE '%Go%')`,
+		},
+		{
+			name:    "wildcards are literal",
+			filter:  constants.FilterReq{Keyword: `50%_off\`},
+			columns: []string{"name"},
+			want:    `SELECT * FROM "rows" WHERE "rows"."name" ILIKE '%50\%\_off\\%'`,
+		},
+		{
+			name:   "date ranges",
+			filter: constants.FilterReq{StartCreatedAt: &start, EndCreatedAt: &end, StartUpdatedAt: &start, EndUpdatedAt: &end},
+			want: `SELECT * FROM "rows" WHERE "rows"."created_at" >= '2024-01-01 00:00:00' AND "rows"."created_at" <= '2024-02-01 00:00:00'` +
+				` AND "rows"."updated_at" >= '2024-01-01 00:00:00' AND "rows"."updated_at" <= '2024-02-01 00:00:00'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scopeSQL(t, FilterScope(tt.filter, tt.columns...)); got != tt.want {
+				t.Errorf("sql =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginateScope(t *testing.T) {
+	filter := constants.FilterReq{Page: 3, Limit: 10, OrderBy: "created_at", SortOrder: "DESC"}
+
+	want := `SELECT * FROM "rows" ORDER BY created_at DESC LIMIT 10 OFFSET 20`
+	if got := scopeSQL(t, PaginateScope(filter)); got != want {
+		t.Errorf("sql =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -23,6 +23,13 @@ type Config struct {
 	RateLimiter RateLimiter `envconfig:"rate_limiter"`
 	RajaOngkir  RajaOngkir  `envconfig:"raja_ongkir"`
 	Midtrans    Midtrans    `envconfig:"midtrans"`
+	Trash       Trash       `envconfig:"trash"`
+	Certificate Certificate `envconfig:"certificate"`
+	Mail        Mail        `envconfig:"mail"`
+	Thumbnail   Thumbnail   `envconfig:"thumbnail"`
+	LinkCheck   LinkCheck   `envconfig:"link_check"`
+	OpenBadge   OpenBadge   `envconfig:"open_badge"`
+	Avatar      Avatar      `envconfig:"avatar"`
 }
 
 type Database struct {
@@ -42,8 +49,10 @@ type Logger struct {
 }
 
 type Auth struct {
-	JWT   JWT   `envconfig:"jwt" validate:"required"`
-	Basic Basic `envconfig:"basic" validate:"required"`
+	JWT           JWT           `envconfig:"jwt" validate:"required"`
+	Basic         Basic         `envconfig:"basic" validate:"required"`
+	PasswordReset PasswordReset `envconfig:"password_reset"`
+	TOTP          TOTP          `envconfig:"totp"`
 }
 
 type GoogleAuth struct {
@@ -52,10 +61,30 @@ type GoogleAuth struct {
 }
 
 type JWT struct {
-	Username  string        `envconfig:"username" validate:"required"`
-	Password  string        `envconfig:"password" validate:"required"`
-	ExpireIn  time.Duration `envconfig:"expire_in" default:"1000m"`
-	SecretKey string        `envconfig:"secret_key" validate:"required"`
+	Username string `envconfig:"username" validate:"required"`
+	Password string `envconfig:"password" validate:"required"`
+	// access tokens are short lived, the refresh token keeps the session going
+	ExpireIn        time.Duration `envconfig:"expire_in" default:"15m"`
+	RefreshExpireIn time.Duration `envconfig:"refresh_expire_in" default:"720h"`
+	SecretKey       string        `envconfig:"secret_key" validate:"required"`
+}
+
+type PasswordReset struct {
+	TokenTTL time.Duration `envconfig:"token_ttl" default:"30m"`
+	// page of the front-end the emailed link opens, the token is added as ?token=
+	LinkUrl string `envconfig:"link_url" default:"http://localhost:5173/reset-password"`
+}
+
+type TOTP struct {
+	// shown next to the account in authenticator apps
+	Issuer string `envconfig:"issuer" default:"devanadindra portfolio"`
+	// time steps accepted either side of now to allow for clock drift
+	Skew          int64         `envconfig:"skew" default:"1"`
+	ChallengeTTL  time.Duration `envconfig:"challenge_ttl" default:"5m"`
+	MaxAttempts   int           `envconfig:"max_attempts" default:"5"`
+	RecoveryCodes int           `envconfig:"recovery_codes" default:"10"`
+	// unexpired challenges an owner can have, used up ones count until they expire
+	MaxChallenges int `envconfig:"max_challenges" default:"3"`
 }
 
 type Basic struct {
@@ -78,6 +107,60 @@ type Midtrans struct {
 	ServerKey       string `envconfig:"server_key_midtrans"`
 }
 
+type Trash struct {
+	Retention     time.Duration `envconfig:"retention" default:"720h"`
+	PurgeInterval time.Duration `envconfig:"purge_interval" default:"24h"`
+}
+
+type Certificate struct {
+	// certificates expiring within this window are reported as expiring_soon
+	ExpiringSoon time.Duration `envconfig:"expiring_soon" default:"720h"`
+	// days before expiry at which the owner is reminded
+	ReminderLeadDays []int         `envconfig:"reminder_lead_days" default:"60,30,7"`
+	ReminderInterval time.Duration `envconfig:"reminder_interval" default:"24h"`
+}
+
+type Mail struct {
+	// smtp, log, file or memory. Only smtp is allowed in production. Without a driver smtp is
+	// used when a host is set and log otherwise.
+	Driver   string `envconfig:"driver" validate:"omitempty,oneof=smtp log file memory"`
+	Dir      string `envconfig:"dir" default:"tmp/mail"`
+	Host     string `envconfig:"host"`
+	Port     int    `envconfig:"port" default:"587"`
+	Username string `envconfig:"username"`
+	Password string `envconfig:"password"`
+	From     string `envconfig:"from" default:"no-reply@devanadindra.com"`
+	// upper bound of an smtp delivery when the caller sets no earlier deadline
+	Timeout time.Duration `envconfig:"timeout" default:"30s"`
+}
+
+type Thumbnail struct {
+	PdftoppmPath string        `envconfig:"pdftoppm_path" default:"pdftoppm"`
+	Size         int           `envconfig:"size" default:"1200"`
+	Timeout      time.Duration `envconfig:"timeout" default:"30s"`
+}
+
+type LinkCheck struct {
+	Interval    time.Duration `envconfig:"interval" default:"24h"`
+	Timeout     time.Duration `envconfig:"timeout" default:"10s"`
+	Concurrency int           `envconfig:"concurrency" default:"5"`
+	UserAgent   string        `envconfig:"user_agent" default:"devanadindra-portfolio-link-checker/1.0"`
+}
+
+type OpenBadge struct {
+	// used when a hosted assertion, badge class, issuer or image has to be fetched
+	Timeout time.Duration `envconfig:"timeout" default:"10s"`
+	MaxSize int64         `envconfig:"max_size" default:"5242880"`
+}
+
+type Avatar struct {
+	MaxSize int64 `envconfig:"max_size" default:"5242880"`
+	// uploads are decoded in memory, larger images are rejected before decoding
+	MaxPixels int `envconfig:"max_pixels" default:"16777216"`
+	// square variants stored for every upload, the largest one is used as avatar_url
+	Sizes []int `envconfig:"sizes" default:"64,256,512"`
+}
+
 var config *Config
 
 func NewConfig() *Config {
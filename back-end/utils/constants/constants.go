@@ -16,4 +16,22 @@ const (
 	QUERY_PARAMS_END_CREATED_AT   = "end-created-at"
 	QUERY_PARAMS_START_UPDATED_AT = "start-updated-at"
 	QUERY_PARAMS_END_UPDATED_AT   = "end-updated-at"
+	QUERY_PARAMS_TAG              = "tag"
+	QUERY_PARAMS_TAG_MODE         = "tag-mode"
+	QUERY_PARAMS_SKILL            = "skill"
+	QUERY_PARAMS_INCLUDE          = "include"
+	QUERY_PARAMS_ISSUER           = "issuer"
+	QUERY_PARAMS_VALIDITY         = "validity"
+	QUERY_PARAMS_GROUP            = "group"
 )
+
+// publication status of projects and certificates
+const (
+	STATUS_DRAFT     = "draft"
+	STATUS_PUBLISHED = "published"
+	STATUS_ARCHIVED  = "archived"
+)
+
+const QUERY_PARAMS_PREVIEW = "preview"
+
+const QUERY_PARAMS_UNREAD = "unread"
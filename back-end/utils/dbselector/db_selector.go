@@ -7,6 +7,7 @@ import (
 	"github.com/devanadindra/portfolio/back-end/utils/constants"
 	contextUtil "github.com/devanadindra/portfolio/back-end/utils/context"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DBService struct {
@@ -37,3 +38,31 @@ func (s *DBService) GetDBByRole(ctx context.Context) (*gorm.DB, error) {
 		return s.VisitorsDB.DB, nil
 	}
 }
+
+func (s *DBService) IsOwner(ctx context.Context) bool {
+	token, err := contextUtil.GetTokenClaims(ctx)
+	if err != nil {
+		return false
+	}
+	return token.Claims.Role == constants.OWNER
+}
+
+// VisibleScope hides drafts, archived rows and rows scheduled in the future from everyone
+// except the owner. The table must have status and publish_at columns.
+func (s *DBService) VisibleScope(ctx context.Context) func(db *gorm.DB) *gorm.DB {
+	isOwner := s.IsOwner(ctx)
+
+	return func(db *gorm.DB) *gorm.DB {
+		if isOwner {
+			return db
+		}
+
+		return db.Where(clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: "status"},
+			Value:  constants.STATUS_PUBLISHED,
+		}).Where(clause.Or(
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "publish_at"}, Value: nil},
+			clause.Expr{SQL: "? <= NOW()", Vars: []any{clause.Column{Table: clause.CurrentTable, Name: "publish_at"}}},
+		))
+	}
+}
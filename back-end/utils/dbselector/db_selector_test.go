@@ -0,0 +1,50 @@
+package dbselector
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/devanadindra/portfolio/back-end/database"
+	"github.com/devanadindra/portfolio/back-end/utils/constants"
+	contextUtil "github.com/devanadindra/portfolio/back-end/utils/context"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+type visibleRow struct {
+	ID string
+}
+
+func (visibleRow) TableName() string {
+	return "rows"
+}
+
+func TestVisibleScope(t *testing.T) {
+	db, _ := testutil.NewMockDB(t)
+	s := NewDBService(&database.OwnerDB{DB: db}, &database.VisitorsDB{DB: db})
+
+	owner := contextUtil.SetTokenClaims(context.Background(), constants.Token{Claims: constants.JWTClaims{Role: constants.OWNER}})
+	visitor := contextUtil.SetTokenClaims(context.Background(), constants.Token{Claims: constants.JWTClaims{Role: constants.VISITORS}})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "owner sees everything", ctx: owner, want: `SELECT * FROM "rows"`},
+		{name: "visitor", ctx: visitor, want: `SELECT * FROM "rows" WHERE "rows"."status" = 'published' AND ("rows"."publish_at" IS NULL OR "rows"."publish_at" <= NOW())`},
+		{name: "anonymous", ctx: context.Background(), want: `SELECT * FROM "rows" WHERE "rows"."status" = 'published' AND ("rows"."publish_at" IS NULL OR "rows"."publish_at" <= NOW())`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return tx.Scopes(s.VisibleScope(tt.ctx)).Find(&[]visibleRow{})
+			})
+			if got != tt.want {
+				t.Errorf("sql =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,25 +2,66 @@ package fileutils
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 )
 
+// content type every accepted image extension must sniff as
+var imageContentTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".bmp":  "image/bmp",
+}
+
+// IsValidImage checks the extension and that the content really is an image of that type,
+// a file renamed to .png is not accepted.
 func IsValidImage(file *multipart.FileHeader) bool {
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	validImageExtensions := []string{".jpg", ".jpeg", ".png", ".gif", ".bmp"}
+	contentType, ok := imageContentTypes[strings.ToLower(filepath.Ext(file.Filename))]
+	if !ok {
+		return false
+	}
 
-	for _, validExt := range validImageExtensions {
-		if ext == validExt {
-			return true
-		}
+	src, err := file.Open()
+	if err != nil {
+		return false
 	}
-	return false
+	defer src.Close()
+
+	// DetectContentType never looks at more than 512 bytes
+	header := make([]byte, 512)
+	n, err := io.ReadFull(src, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return false
+	}
+	return http.DetectContentType(header[:n]) == contentType
+}
+
+// IsValidPDF checks both the extension and the "%PDF-" signature at the start of the file.
+func IsValidPDF(file *multipart.FileHeader) bool {
+	if strings.ToLower(filepath.Ext(file.Filename)) != ".pdf" {
+		return false
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return false
+	}
+	defer src.Close()
+
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(src, header); err != nil {
+		return false
+	}
+	return string(header) == "%PDF-"
 }
 
 func IsValidVideo(file *multipart.FileHeader) bool {
@@ -83,3 +124,18 @@ func SaveMedia(ctx context.Context, file *multipart.FileHeader, path string) err
 
 	return nil
 }
+
+// RemoveMedia deletes a stored media file by its public url ("/uploads/...").
+// Missing files are ignored.
+func RemoveMedia(url string) error {
+	if url == "" {
+		return nil
+	}
+
+	cleanPath := strings.TrimPrefix(url, "/")
+	if err := os.Remove(cleanPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed delete file %s: %w", cleanPath, err)
+	}
+
+	return nil
+}
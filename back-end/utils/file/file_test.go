@@ -0,0 +1,58 @@
+package fileutils
+
+import (
+	"bytes"
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func pngBytes(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestIsValidImage(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+		want     bool
+	}{
+		{"png", "photo.png", pngBytes(t), true},
+		{"upper case extension", "PHOTO.PNG", pngBytes(t), true},
+		{"jpeg", "photo.jpg", []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"), true},
+		{"gif", "anim.gif", []byte("GIF89a\x01\x00\x01\x00"), true},
+		{"renamed text", "evil.png", []byte("<script>alert(1)</script>"), false},
+		{"html renamed to jpg", "page.jpg", []byte("<!DOCTYPE html><html></html>"), false},
+		{"content does not match extension", "photo.gif", pngBytes(t), false},
+		{"unknown extension", "photo.webp", pngBytes(t), false},
+		{"empty", "photo.png", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidImage(testutil.FormFile(t, tt.filename, tt.data)); got != tt.want {
+				t.Errorf("IsValidImage(%q) = %v, want %v", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidPDF(t *testing.T) {
+	if !IsValidPDF(testutil.FormFile(t, "cert.pdf", []byte("%PDF-1.7\n"))) {
+		t.Error("a pdf should be accepted")
+	}
+	if IsValidPDF(testutil.FormFile(t, "cert.pdf", pngBytes(t))) {
+		t.Error("a png named .pdf should be rejected")
+	}
+	if IsValidPDF(testutil.FormFile(t, "cert.txt", []byte("%PDF-1.7\n"))) {
+		t.Error("a pdf without the .pdf extension should be rejected")
+	}
+}
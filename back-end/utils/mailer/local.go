@@ -0,0 +1,63 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+)
+
+type fileSender struct {
+	from string
+	dir  string
+}
+
+// NewFileSender writes every email as an .eml file into the mail dir, which makes links
+// like password resets easy to follow on local runs.
+func NewFileSender(conf config.Mail) Sender {
+	return &fileSender{from: conf.From, dir: conf.Dir}
+}
+
+func (s *fileSender) Send(ctx context.Context, msg Message) error {
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dir, fmt.Sprintf("%d.eml", time.Now().UnixNano()))
+	if err := os.WriteFile(path, buildMessage(s.from, msg), 0o600); err != nil {
+		return fmt.Errorf("failed write email %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// MemorySender keeps sent emails in memory instead of delivering them.
+type MemorySender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+func (s *MemorySender) Send(ctx context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+// Messages returns the emails sent so far, oldest first.
+func (s *MemorySender) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.messages)
+}
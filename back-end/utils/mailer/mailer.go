@@ -0,0 +1,164 @@
+package mailer
+
+import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/logger"
+)
+
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Sender delivers emails. Implementations must be safe for concurrent use.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns the sender picked by the mail driver. The other drivers keep emails on the
+// server, they are meant for local runs and refused in production. Without a driver smtp is
+// used when a host is set, otherwise emails are only logged so that a deployment without mail
+// settings still starts.
+func New(conf *config.Config) (Sender, error) {
+	if conf.Mail.Driver == "" && conf.Mail.Host == "" {
+		logger.Warn(context.Background(), "MAIL_HOST is not set, emails are logged instead of sent")
+		return &logSender{}, nil
+	}
+
+	driver := cmp.Or(conf.Mail.Driver, "smtp")
+	if driver != "smtp" && conf.Environment == config.PRODUCTION_ENVIRONMENT {
+		return nil, fmt.Errorf("mail driver %s is not allowed in production", driver)
+	}
+
+	switch driver {
+	case "log":
+		return &logSender{}, nil
+	case "file":
+		return NewFileSender(conf.Mail), nil
+	case "memory":
+		return NewMemorySender(), nil
+	}
+
+	if conf.Mail.Host == "" {
+		return nil, errors.New("mail host is not set, use the log or file mail driver to run without a mail server")
+	}
+	return NewSMTPSender(conf.Mail), nil
+}
+
+type smtpSender struct {
+	conf config.Mail
+}
+
+func NewSMTPSender(conf config.Mail) Sender {
+	return &smtpSender{conf: conf}
+}
+
+func (s *smtpSender) Send(ctx context.Context, msg Message) error {
+	if s.conf.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.conf.Timeout)
+		defer cancel()
+	}
+
+	if err := s.send(ctx, msg); err != nil {
+		return fmt.Errorf("failed send email to %s: %w", strings.Join(msg.To, ", "), err)
+	}
+
+	return nil
+}
+
+// send does what smtp.SendMail does, over a connection that gives up once ctx is done.
+// smtp.SendMail has no deadline and would wait forever on a server that stops answering.
+func (s *smtpSender) send(ctx context.Context, msg Message) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.conf.Host, strconv.Itoa(s.conf.Port)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	// a cancel without a deadline still has to unblock the exchange
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, s.conf.Host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.conf.Host}); err != nil {
+			return err
+		}
+	}
+	if s.conf.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.conf.Username, s.conf.Password, s.conf.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.conf.From); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buildMessage(s.conf.From, msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// logSender only logs that an email was sent. The body is left out, it can hold links like
+// password resets that must not end up in the logs.
+type logSender struct{}
+
+func (s *logSender) Send(ctx context.Context, msg Message) error {
+	logger.Info(ctx, "email to %s : %s (%d bytes, body not logged)", strings.Join(msg.To, ", "), msg.Subject, len(msg.Body))
+	return nil
+}
+
+var headerEscaper = strings.NewReplacer("\r", " ", "\n", " ")
+
+func buildMessage(from string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + strings.Join(msg.To, ", ") + "\r\n")
+	// the subject may contain user content, it must not be able to add headers
+	b.WriteString("Subject: " + headerEscaper.Replace(msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
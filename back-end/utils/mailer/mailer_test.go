@@ -0,0 +1,233 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment config.Environment
+		mail        config.Mail
+		want        string
+		wantErr     bool
+	}{
+		{name: "smtp by default", environment: config.DEVELOPMENT_ENVIRONMENT, mail: config.Mail{Host: "smtp.example.com"}, want: "*mailer.smtpSender"},
+		{name: "log without a host", environment: config.DEVELOPMENT_ENVIRONMENT, want: "*mailer.logSender"},
+		{name: "log without a host in production", environment: config.PRODUCTION_ENVIRONMENT, want: "*mailer.logSender"},
+		{name: "explicit smtp without host", environment: config.DEVELOPMENT_ENVIRONMENT, mail: config.Mail{Driver: "smtp"}, wantErr: true},
+		{name: "log", environment: config.DEVELOPMENT_ENVIRONMENT, mail: config.Mail{Driver: "log"}, want: "*mailer.logSender"},
+		{name: "file", environment: config.TEST_ENVIRONMENT, mail: config.Mail{Driver: "file"}, want: "*mailer.fileSender"},
+		{name: "memory", environment: config.TEST_ENVIRONMENT, mail: config.Mail{Driver: "memory"}, want: "*mailer.MemorySender"},
+		{name: "smtp in production", environment: config.PRODUCTION_ENVIRONMENT, mail: config.Mail{Host: "smtp.example.com"}, want: "*mailer.smtpSender"},
+		{name: "log refused in production", environment: config.PRODUCTION_ENVIRONMENT, mail: config.Mail{Driver: "log", Host: "smtp.example.com"}, wantErr: true},
+		{name: "file refused in production", environment: config.PRODUCTION_ENVIRONMENT, mail: config.Mail{Driver: "file"}, wantErr: true},
+		{name: "memory refused in production", environment: config.PRODUCTION_ENVIRONMENT, mail: config.Mail{Driver: "memory"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender, err := New(&config.Config{Environment: tt.environment, Mail: tt.mail})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("New() = %T, want an error", sender)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if got := fmt.Sprintf("%T", sender); got != tt.want {
+				t.Errorf("New() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogSenderRedactsBody(t *testing.T) {
+	var out bytes.Buffer
+	logrus.SetOutput(&out)
+	t.Cleanup(func() { logrus.SetOutput(os.Stderr) })
+
+	msg := Message{To: []string{"owner@example.com"}, Subject: "Reset your password", Body: "https://example.com/reset?token=secret-token"}
+	if err := (&logSender{}).Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	logged := out.String()
+	if strings.Contains(logged, "secret-token") {
+		t.Errorf("log contains the body: %s", logged)
+	}
+	if !strings.Contains(logged, "owner@example.com") || !strings.Contains(logged, "Reset your password") {
+		t.Errorf("log misses recipient or subject: %s", logged)
+	}
+}
+
+func TestBuildMessageEscapesSubject(t *testing.T) {
+	msg := Message{To: []string{"owner@example.com"}, Subject: "Hello\r\nBcc: attacker@example.com", Body: "line one\nline two"}
+	raw := string(buildMessage("no-reply@example.com", msg))
+
+	if strings.Contains(raw, "\r\nBcc:") {
+		t.Errorf("subject added a header:\n%s", raw)
+	}
+	if !strings.Contains(raw, "Subject: Hello  Bcc: attacker@example.com\r\n") {
+		t.Errorf("subject not kept on one line:\n%s", raw)
+	}
+	if !strings.HasSuffix(raw, "\r\n\r\nline one\r\nline two") {
+		t.Errorf("body not separated or not using CRLF:\n%q", raw)
+	}
+}
+
+func TestFileSender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	sender := NewFileSender(config.Mail{Dir: dir, From: "no-reply@example.com"})
+
+	if err := sender.Send(context.Background(), Message{To: []string{"owner@example.com"}, Subject: "Hi", Body: "body"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("got files %v (err %v), want one .eml", files, err)
+	}
+	raw, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), "From: no-reply@example.com\r\n") || !strings.HasSuffix(string(raw), "\r\nbody") {
+		t.Errorf("unexpected email:\n%s", raw)
+	}
+}
+
+func TestMemorySender(t *testing.T) {
+	sender := NewMemorySender()
+	for _, subject := range []string{"first", "second"} {
+		if err := sender.Send(context.Background(), Message{Subject: subject}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	messages := sender.Messages()
+	if len(messages) != 2 || messages[0].Subject != "first" || messages[1].Subject != "second" {
+		t.Fatalf("Messages() = %+v", messages)
+	}
+
+	// the returned slice is a copy
+	messages[0].Subject = "changed"
+	if sender.Messages()[0].Subject != "first" {
+		t.Error("Messages() exposes the internal slice")
+	}
+}
+
+// fakeSMTP listens on a local port and hands every connection to serve.
+func fakeSMTP(t *testing.T, serve func(conn net.Conn)) config.Mail {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				serve(conn)
+			}()
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return config.Mail{Host: "127.0.0.1", Port: addr.Port, From: "no-reply@example.com"}
+}
+
+func TestSMTPSenderSends(t *testing.T) {
+	received := make(chan string, 1)
+	conf := fakeSMTP(t, func(conn net.Conn) {
+		tp := textproto.NewConn(conn)
+		tp.PrintfLine("220 fake ready")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				tp.PrintfLine("250 fake")
+			case line == "DATA":
+				tp.PrintfLine("354 go ahead")
+				body, _ := tp.ReadDotBytes()
+				received <- string(body)
+				tp.PrintfLine("250 queued")
+			case line == "QUIT":
+				tp.PrintfLine("221 bye")
+				return
+			default:
+				tp.PrintfLine("250 ok")
+			}
+		}
+	})
+
+	msg := Message{To: []string{"owner@example.com"}, Subject: "Hi", Body: "body"}
+	if err := NewSMTPSender(conf).Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	select {
+	case body := <-received:
+		if !strings.Contains(body, "Subject: Hi") || !strings.HasSuffix(body, "\nbody\n") {
+			t.Errorf("unexpected email:\n%s", body)
+		}
+	default:
+		t.Fatal("server got no email")
+	}
+}
+
+// a server that accepts the connection but never answers must not block the caller
+func TestSMTPSenderGivesUpAtDeadline(t *testing.T) {
+	conf := fakeSMTP(t, func(conn net.Conn) {
+		io.Copy(io.Discard, conn)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := NewSMTPSender(conf).Send(ctx, Message{To: []string{"owner@example.com"}, Subject: "Hi"})
+	if err == nil {
+		t.Fatal("Send() succeeded against a silent server")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Send() returned after %s, want it to stop at the deadline", elapsed)
+	}
+}
+
+func TestSMTPSenderTimeout(t *testing.T) {
+	conf := fakeSMTP(t, func(conn net.Conn) {
+		io.Copy(io.Discard, conn)
+	})
+	conf.Timeout = 100 * time.Millisecond
+
+	if err := NewSMTPSender(conf).Send(context.Background(), Message{To: []string{"owner@example.com"}, Subject: "Hi"}); err == nil {
+		t.Fatal("Send() succeeded against a silent server")
+	}
+}
@@ -0,0 +1,224 @@
+package openbadge
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+)
+
+// Badge is what a certificate needs from an assertion, whatever the Open Badges version.
+type Badge struct {
+	Name   string
+	Issuer string
+	// url or data uri of the badge image, empty when the badge has none
+	Image     string
+	IssuedAt  time.Time
+	ExpiresAt *time.Time
+	// page or hosted assertion where the badge can be verified
+	VerificationUrl string
+	// id of the assertion or credential
+	CredentialID string
+	Tags         []string
+}
+
+// node is a property that may be embedded as an object or referenced by its id.
+type node struct {
+	ID  string
+	Raw json.RawMessage
+}
+
+func (n *node) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		n.ID = id
+		return nil
+	}
+
+	var obj struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	n.ID = obj.ID
+	n.Raw = data
+	return nil
+}
+
+func (n node) embedded() bool {
+	return len(n.Raw) > 0
+}
+
+// stringList accepts both a single string and an array, "type" and "@context" use both forms.
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*l = stringList{one}
+		return nil
+	}
+
+	var many []json.RawMessage
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	for _, raw := range many {
+		// contexts may also hold inline objects, only the urls matter here
+		if err := json.Unmarshal(raw, &one); err == nil {
+			*l = append(*l, one)
+		}
+	}
+	return nil
+}
+
+type probe struct {
+	Context stringList      `json:"@context"`
+	Type    stringList      `json:"type"`
+	VC      json.RawMessage `json:"vc"`
+}
+
+type assertionV2 struct {
+	ID       string          `json:"id"`
+	Badge    node            `json:"badge"`
+	Image    node            `json:"image"`
+	IssuedOn json.RawMessage `json:"issuedOn"`
+	Expires  json.RawMessage `json:"expires"`
+	Verify   *struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"verify"`
+	Revoked bool `json:"revoked"`
+}
+
+type badgeClassV2 struct {
+	Name   string     `json:"name"`
+	Image  node       `json:"image"`
+	Issuer node       `json:"issuer"`
+	Tags   stringList `json:"tags"`
+}
+
+type credentialV3 struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	Image             node   `json:"image"`
+	Issuer            node   `json:"issuer"`
+	ValidFrom         string `json:"validFrom"`
+	IssuanceDate      string `json:"issuanceDate"`
+	ValidUntil        string `json:"validUntil"`
+	ExpirationDate    string `json:"expirationDate"`
+	CredentialSubject struct {
+		Achievement *struct {
+			Name  string     `json:"name"`
+			Image node       `json:"image"`
+			Tag   stringList `json:"tag"`
+		} `json:"achievement"`
+	} `json:"credentialSubject"`
+}
+
+type profile struct {
+	Name string `json:"name"`
+}
+
+func isV3(p probe) bool {
+	for _, t := range p.Type {
+		if t == "OpenBadgeCredential" || t == "AchievementCredential" {
+			return true
+		}
+	}
+	for _, c := range p.Context {
+		if strings.Contains(c, "purl.imsglobal.org/spec/ob/v3") {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeJWS returns the payload of a signed assertion. Signatures are not verified, the
+// verification url stored with the certificate is where visitors check the badge.
+func decodeJWS(compact string) ([]byte, bool) {
+	parts := strings.Split(compact, ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil || !json.Valid(payload) {
+		return nil, false
+	}
+
+	// 3.0 credentials signed as a VC-JWT may wrap the credential in a "vc" claim
+	var p probe
+	if err := json.Unmarshal(payload, &p); err == nil && len(p.VC) > 0 {
+		return p.VC, true
+	}
+	return payload, true
+}
+
+// parseDate accepts ISO 8601 date times and dates, 2.0 also allows unix timestamps.
+func parseDate(raw json.RawMessage, field string) (*time.Time, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		var seconds int64
+		if err := json.Unmarshal(raw, &seconds); err != nil {
+			return nil, apierror.InvalidBadgeAssertion(field + " is not a date")
+		}
+		t := time.Unix(seconds, 0).UTC()
+		return &t, nil
+	}
+
+	return parseDateString(value, field)
+}
+
+func parseDateString(value string, field string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		t := time.Unix(seconds, 0).UTC()
+		return &t, nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, apierror.InvalidBadgeAssertion(field + " '" + value + "' is not an ISO 8601 date")
+}
+
+func isHTTPUrl(value string) bool {
+	return strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://")
+}
+
+// imageOf returns the first image set, as an url or a data uri.
+func imageOf(images ...node) string {
+	for _, img := range images {
+		if img.ID != "" {
+			return img.ID
+		}
+	}
+	return ""
+}
+
+func cleanTags(tags []string) []string {
+	res := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if t = strings.TrimSpace(t); t != "" && !slices.Contains(res, t) {
+			res = append(res, t)
+		}
+	}
+	return res
+}
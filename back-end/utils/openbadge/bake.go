@@ -0,0 +1,160 @@
+package openbadge
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// keywords of the PNG text chunk holding the assertion, "openbadges" for 2.0 and
+// "openbadgecredential" for 3.0
+var pngKeywords = map[string]bool{
+	"openbadges":          true,
+	"openbadgecredential": true,
+}
+
+var errNotBaked = errors.New("no assertion baked into the file")
+
+func isPNG(data []byte) bool {
+	return bytes.HasPrefix(data, pngSignature)
+}
+
+func isSVG(data []byte) bool {
+	head := data[:min(len(data), 1024)]
+	return bytes.Contains(head, []byte("<svg"))
+}
+
+// IsBadgeImage reports whether data is a PNG or SVG, the formats a badge can be baked into.
+func IsBadgeImage(data []byte) bool {
+	return isPNG(data) || isSVG(data)
+}
+
+// unbakePNG returns the text of the iTXt (or tEXt for old 1.x badges) chunk carrying the assertion.
+func unbakePNG(data []byte) (string, error) {
+	r := bytes.NewReader(data[len(pngSignature):])
+
+	for {
+		var header struct {
+			Length uint32
+			Type   [4]byte
+		}
+		if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+			return "", errNotBaked
+		}
+		if int64(header.Length) > int64(r.Len()) {
+			return "", errors.New("truncated PNG chunk")
+		}
+
+		chunk := make([]byte, header.Length)
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return "", err
+		}
+		// crc
+		if _, err := r.Seek(4, io.SeekCurrent); err != nil {
+			return "", err
+		}
+
+		switch string(header.Type[:]) {
+		case "iTXt":
+			if text, ok, err := parseITXt(chunk); ok || err != nil {
+				return text, err
+			}
+		case "tEXt":
+			keyword, text, found := bytes.Cut(chunk, []byte{0})
+			if found && pngKeywords[string(keyword)] {
+				return string(text), nil
+			}
+		case "IEND":
+			return "", errNotBaked
+		}
+	}
+}
+
+// parseITXt decodes an iTXt chunk: keyword, null, compression flag, compression method,
+// language tag, null, translated keyword, null, text.
+func parseITXt(chunk []byte) (text string, ok bool, err error) {
+	keyword, rest, found := bytes.Cut(chunk, []byte{0})
+	if !found || !pngKeywords[string(keyword)] || len(rest) < 2 {
+		return "", false, nil
+	}
+
+	compressed := rest[0] == 1
+	rest = rest[2:]
+	// language tag and translated keyword are not needed
+	for range 2 {
+		if _, rest, found = bytes.Cut(rest, []byte{0}); !found {
+			return "", true, errors.New("malformed iTXt chunk")
+		}
+	}
+
+	if !compressed {
+		return string(rest), true, nil
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(rest))
+	if err != nil {
+		return "", true, err
+	}
+	defer zr.Close()
+
+	inflated, err := io.ReadAll(zr)
+	if err != nil {
+		return "", true, err
+	}
+	return string(inflated), true, nil
+}
+
+// unbakeSVG returns the content of the openbadges:assertion (2.0) or openbadges:credential (3.0)
+// element. A hosted 2.0 assertion may be baked with an empty element and only its verify url.
+func unbakeSVG(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return "", errNotBaked
+		}
+		if err != nil {
+			return "", err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || !isBadgeElement(start.Name) {
+			continue
+		}
+
+		var element struct {
+			Verify  string `xml:"verify,attr"`
+			Content string `xml:",chardata"`
+		}
+		if err := decoder.DecodeElement(&element, &start); err != nil {
+			return "", err
+		}
+
+		if content := strings.TrimSpace(element.Content); content != "" {
+			return content, nil
+		}
+		if element.Verify != "" {
+			return element.Verify, nil
+		}
+		return "", errNotBaked
+	}
+}
+
+// isBadgeElement matches the element either by its resolved namespace or, when the namespace
+// was not declared, by its "openbadges" prefix.
+func isBadgeElement(name xml.Name) bool {
+	if name.Local != "assertion" && name.Local != "credential" {
+		return false
+	}
+	return name.Space == "openbadges" ||
+		strings.Contains(name.Space, "openbadges.org") ||
+		strings.Contains(name.Space, "purl.imsglobal.org")
+}
@@ -0,0 +1,86 @@
+package openbadge
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+)
+
+// http redirects followed for a single fetch
+const maxHTTPRedirects = 3
+
+var errNotPublic = errors.New("address is not public")
+
+// ranges that are not reachable on the internet and not covered by the netip helpers
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// HTTPClient is the part of *http.Client used by the resolver, tests can pass a client
+// pointing to an httptest server.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// NewHTTPClient only connects to public addresses. Badge urls come from uploaded files, so
+// they must not reach the database, a cloud metadata service or anything else next to the
+// server. The address is checked after the name is resolved, for every redirect as well.
+func NewHTTPClient(conf *config.Config) HTTPClient {
+	dialer := &net.Dialer{
+		Timeout: conf.OpenBadge.Timeout,
+		Control: publicOnly,
+	}
+
+	return &http.Client{
+		Timeout: conf.OpenBadge.Timeout,
+		Transport: &http.Transport{
+			// no proxy, it would be the only address checked
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxHTTPRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxHTTPRedirects)
+			}
+			return nil
+		},
+	}
+}
+
+func publicOnly(network string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !isPublic(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", errNotPublic, address)
+	}
+	return nil
+}
+
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package openbadge
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+)
+
+func newTestConfig() *config.Config {
+	conf := &config.Config{}
+	conf.OpenBadge = config.OpenBadge{Timeout: 5 * time.Second, MaxSize: 1 << 20}
+	return conf
+}
+
+func TestIsPublic(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.215.14":        true,
+		"2606:4700::1111":      true,
+		"127.0.0.1":            false,
+		"10.1.2.3":             false,
+		"172.16.0.1":           false,
+		"192.168.1.1":          false,
+		"169.254.169.254":      false,
+		"100.64.0.1":           false,
+		"0.0.0.0":              false,
+		"::1":                  false,
+		"fe80::1":              false,
+		"fd00::1":              false,
+		"::ffff:127.0.0.1":     false,
+		"::ffff:93.184.215.14": true,
+		"224.0.0.1":            false,
+	}
+
+	for raw, want := range tests {
+		if got := isPublic(netip.MustParseAddr(raw)); got != want {
+			t.Errorf("isPublic(%s) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestHTTPClientRefusesLocalAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the local server was reached")
+	}))
+	defer srv.Close()
+
+	// localhost resolves to a loopback address, the check runs after resolution
+	for _, rawUrl := range []string{srv.URL, strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)} {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, rawUrl, nil)
+		_, err := NewHTTPClient(newTestConfig()).Do(req)
+		if !errors.Is(err, errNotPublic) {
+			t.Errorf("%s: err = %v, want errNotPublic", rawUrl, err)
+		}
+	}
+}
+
+func TestHTTPClientRedirectLimit(t *testing.T) {
+	hops := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hops++
+		http.Redirect(w, r, "/again", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	// the redirect policy is tested on its own, the dialer would refuse the local server
+	client := NewHTTPClient(newTestConfig()).(*http.Client)
+	client.Transport = srv.Client().Transport
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("expected the redirect loop to be stopped")
+	}
+	if hops != maxHTTPRedirects {
+		t.Errorf("requests = %d, want %d", hops, maxHTTPRedirects)
+	}
+}
@@ -0,0 +1,352 @@
+package openbadge
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+	"github.com/devanadindra/portfolio/back-end/utils/common"
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+	"github.com/devanadindra/portfolio/back-end/utils/logger"
+)
+
+// a hosted assertion may point to another url (baked url -> assertion), never further
+const maxRedirections = 2
+
+// Resolver turns a baked badge or an assertion into a Badge, fetching the hosted parts
+// (assertion, badge class, issuer) it references.
+type Resolver interface {
+	// Resolve accepts a baked PNG or SVG, an assertion JSON, a signed assertion or an
+	// assertion url. filename is only used in error messages.
+	Resolve(ctx context.Context, data []byte, filename string) (*Badge, error)
+	// Image downloads or decodes the image of a badge and returns it with its file extension.
+	Image(ctx context.Context, src string) ([]byte, string, error)
+}
+
+type resolver struct {
+	client  HTTPClient
+	maxSize int64
+}
+
+func NewResolver(conf *config.Config, client HTTPClient) Resolver {
+	return &resolver{
+		client:  client,
+		maxSize: conf.OpenBadge.MaxSize,
+	}
+}
+
+func (r *resolver) Resolve(ctx context.Context, data []byte, filename string) (*Badge, error) {
+	if int64(len(data)) > r.maxSize {
+		return nil, apierror.BadgeTooLarge(r.maxSize)
+	}
+
+	var payload string
+	var err error
+	switch {
+	case isPNG(data):
+		payload, err = unbakePNG(data)
+	case isSVG(data):
+		payload, err = unbakeSVG(data)
+	default:
+		payload = strings.TrimSpace(string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+		if !strings.HasPrefix(payload, "{") && !isHTTPUrl(payload) {
+			if _, ok := decodeJWS(payload); !ok {
+				return nil, apierror.InvalidBadgeFile(filename)
+			}
+		}
+	}
+	if errors.Is(err, errNotBaked) {
+		return nil, apierror.BadgeAssertionNotFound(filename)
+	}
+	if err != nil {
+		return nil, apierror.InvalidBadgeFile(filename)
+	}
+
+	return r.resolvePayload(ctx, payload, 0)
+}
+
+func (r *resolver) resolvePayload(ctx context.Context, payload string, depth int) (*Badge, error) {
+	payload = strings.TrimSpace(payload)
+
+	var data []byte
+	switch {
+	case isHTTPUrl(payload):
+		if depth >= maxRedirections {
+			return nil, apierror.InvalidBadgeAssertion("the hosted assertion does not contain the assertion itself")
+		}
+		body, err := r.fetch(ctx, payload)
+		if err != nil {
+			return nil, err
+		}
+		return r.resolvePayload(ctx, string(body), depth+1)
+	case strings.HasPrefix(payload, "{"):
+		data = []byte(payload)
+	default:
+		decoded, ok := decodeJWS(payload)
+		if !ok {
+			return nil, apierror.InvalidBadgeAssertion("expected an assertion JSON, a signed assertion or its url")
+		}
+		data = decoded
+	}
+
+	var p probe
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, apierror.InvalidBadgeAssertion("not valid JSON")
+	}
+
+	switch {
+	case isV3(p):
+		return r.fromV3(ctx, data)
+	case slices.Contains(p.Type, "BadgeClass"):
+		return nil, apierror.InvalidBadgeAssertion("this is a badge class, import the assertion awarded to you instead")
+	case slices.Contains(p.Type, "Issuer") || slices.Contains(p.Type, "Profile"):
+		return nil, apierror.InvalidBadgeAssertion("this is an issuer profile, import the assertion awarded to you instead")
+	default:
+		return r.fromV2(ctx, data)
+	}
+}
+
+func (r *resolver) fromV2(ctx context.Context, data []byte) (*Badge, error) {
+	var assertion assertionV2
+	if err := json.Unmarshal(data, &assertion); err != nil {
+		return nil, apierror.InvalidBadgeAssertion(fmt.Sprintf("malformed 2.0 assertion (%v)", err))
+	}
+
+	if assertion.Revoked {
+		return nil, apierror.BadgeRevoked()
+	}
+
+	var badgeClass badgeClassV2
+	if err := r.load(ctx, assertion.Badge, &badgeClass, "badge"); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(badgeClass.Name) == "" {
+		return nil, apierror.InvalidBadgeAssertion("the badge has no name")
+	}
+
+	var issuer profile
+	if err := r.load(ctx, badgeClass.Issuer, &issuer, "issuer"); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(issuer.Name) == "" {
+		return nil, apierror.InvalidBadgeAssertion("the issuer has no name")
+	}
+
+	issuedOn, err := parseDate(assertion.IssuedOn, "issuedOn")
+	if err != nil {
+		return nil, err
+	}
+	if issuedOn == nil {
+		return nil, apierror.InvalidBadgeAssertion("issuedOn is missing")
+	}
+
+	expires, err := parseDate(assertion.Expires, "expires")
+	if err != nil {
+		return nil, err
+	}
+
+	var verificationUrl string
+	if assertion.Verify != nil && assertion.Verify.Type == "hosted" && isHTTPUrl(assertion.Verify.URL) {
+		verificationUrl = assertion.Verify.URL
+	} else if isHTTPUrl(assertion.ID) {
+		verificationUrl = assertion.ID
+	}
+
+	return &Badge{
+		Name:            strings.TrimSpace(badgeClass.Name),
+		Issuer:          strings.TrimSpace(issuer.Name),
+		Image:           imageOf(assertion.Image, badgeClass.Image),
+		IssuedAt:        *issuedOn,
+		ExpiresAt:       expires,
+		VerificationUrl: verificationUrl,
+		CredentialID:    assertion.ID,
+		Tags:            cleanTags(badgeClass.Tags),
+	}, nil
+}
+
+func (r *resolver) fromV3(ctx context.Context, data []byte) (*Badge, error) {
+	var credential credentialV3
+	if err := json.Unmarshal(data, &credential); err != nil {
+		return nil, apierror.InvalidBadgeAssertion(fmt.Sprintf("malformed 3.0 credential (%v)", err))
+	}
+
+	achievement := credential.CredentialSubject.Achievement
+	if achievement == nil {
+		return nil, apierror.InvalidBadgeAssertion("credentialSubject.achievement is missing")
+	}
+
+	name := strings.TrimSpace(achievement.Name)
+	if name == "" {
+		name = strings.TrimSpace(credential.Name)
+	}
+	if name == "" {
+		return nil, apierror.InvalidBadgeAssertion("the achievement has no name")
+	}
+
+	var issuer profile
+	if err := r.load(ctx, credential.Issuer, &issuer, "issuer"); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(issuer.Name) == "" {
+		return nil, apierror.InvalidBadgeAssertion("the issuer has no name")
+	}
+
+	// validFrom/validUntil are the 3.0 names, issuanceDate/expirationDate come from VC 1.1
+	issuedAt, err := parseDateString(cmp.Or(credential.ValidFrom, credential.IssuanceDate), "validFrom")
+	if err != nil {
+		return nil, err
+	}
+	if issuedAt == nil {
+		return nil, apierror.InvalidBadgeAssertion("validFrom is missing")
+	}
+
+	expiresAt, err := parseDateString(cmp.Or(credential.ValidUntil, credential.ExpirationDate), "validUntil")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Badge{
+		Name:            name,
+		Issuer:          strings.TrimSpace(issuer.Name),
+		Image:           imageOf(achievement.Image, credential.Image),
+		IssuedAt:        *issuedAt,
+		ExpiresAt:       expiresAt,
+		VerificationUrl: common.Ternary(isHTTPUrl(credential.ID), credential.ID, ""),
+		CredentialID:    credential.ID,
+		Tags:            cleanTags(achievement.Tag),
+	}, nil
+}
+
+// load decodes an embedded node into v, or fetches it when it is only referenced by url.
+func (r *resolver) load(ctx context.Context, n node, v any, field string) error {
+	data := []byte(n.Raw)
+	if !n.embedded() {
+		if !isHTTPUrl(n.ID) {
+			return apierror.InvalidBadgeAssertion(field + " must be embedded or referenced by an http(s) url")
+		}
+
+		body, err := r.fetch(ctx, n.ID)
+		if err != nil {
+			return err
+		}
+		data = body
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return apierror.InvalidBadgeAssertion(field + " is not a valid JSON object")
+	}
+	return nil
+}
+
+func (r *resolver) Image(ctx context.Context, src string) ([]byte, string, error) {
+	var data []byte
+	switch {
+	case strings.HasPrefix(src, "data:"):
+		meta, content, found := strings.Cut(strings.TrimPrefix(src, "data:"), ",")
+		if !found {
+			return nil, "", apierror.InvalidBadgeAssertion("the badge image is a malformed data url")
+		}
+
+		var err error
+		if strings.HasSuffix(meta, ";base64") {
+			data, err = base64.StdEncoding.DecodeString(content)
+		} else {
+			var unescaped string
+			unescaped, err = url.PathUnescape(content)
+			data = []byte(unescaped)
+		}
+		if err != nil {
+			return nil, "", apierror.InvalidBadgeAssertion("the badge image is a malformed data url")
+		}
+	case isHTTPUrl(src):
+		body, err := r.fetch(ctx, src)
+		if err != nil {
+			return nil, "", err
+		}
+		data = body
+	case src == "":
+		return nil, "", apierror.InvalidBadgeAssertion("the badge has no image")
+	default:
+		return nil, "", apierror.InvalidBadgeAssertion("the badge image must be an http(s) or data url")
+	}
+
+	return SafeImage(data)
+}
+
+func (r *resolver) fetch(ctx context.Context, rawUrl string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawUrl, nil)
+	if err != nil {
+		return nil, apierror.BadgeFetchFailed(rawUrl)
+	}
+	req.Header.Set("Accept", "application/ld+json, application/json;q=0.9, */*;q=0.8")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		logger.Error(ctx, "%v", err)
+		return nil, apierror.BadgeFetchFailed(rawUrl)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusGone {
+		return nil, apierror.BadgeRevoked()
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, apierror.BadgeFetchFailed(rawUrl)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, r.maxSize+1))
+	if err != nil {
+		logger.Error(ctx, "%v", err)
+		return nil, apierror.BadgeFetchFailed(rawUrl)
+	}
+	if int64(len(body)) > r.maxSize {
+		return nil, apierror.BadgeTooLarge(r.maxSize)
+	}
+
+	return body, nil
+}
+
+// SafeImage returns a badge image that can be served as is, with its file extension. SVGs
+// are rebuilt from drawing elements only so a badge cannot carry script.
+func SafeImage(data []byte) ([]byte, string, error) {
+	ext := ImageExt(data)
+	if ext == "" {
+		return nil, "", apierror.InvalidBadgeAssertion("the badge image is not a PNG, SVG, JPEG or GIF")
+	}
+
+	if ext == ".svg" {
+		clean, err := SanitizeSVG(data)
+		if err != nil {
+			return nil, "", apierror.InvalidBadgeAssertion("the badge image is not a valid SVG")
+		}
+		data = clean
+	}
+
+	return data, ext, nil
+}
+
+// ImageExt returns the file extension of a PNG, JPEG, GIF or SVG image, or "" for anything else.
+func ImageExt(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	}
+	if isSVG(data) {
+		return ".svg"
+	}
+	return ""
+}
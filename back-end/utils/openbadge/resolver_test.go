@@ -0,0 +1,141 @@
+package openbadge
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/devanadindra/portfolio/back-end/utils/testutil"
+)
+
+// newHostedBadge serves a 2.0 assertion whose badge class, issuer and image are hosted.
+func newHostedBadge(t *testing.T) (*httptest.Server, Resolver) {
+	t.Helper()
+
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/assertion", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"type":"Assertion","id":"` + srv.URL + `/assertion","badge":"` + srv.URL + `/badge",` +
+			`"issuedOn":"2024-05-01T00:00:00Z","verify":{"type":"hosted","url":"` + srv.URL + `/assertion"}}`))
+	})
+	mux.HandleFunc("/badge", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":" Go Developer ","issuer":"` + srv.URL + `/issuer","image":"` + srv.URL + `/image.svg","tags":["go","Go",""]}`))
+	})
+	mux.HandleFunc("/issuer", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"Example Academy"}`))
+	})
+	mux.HandleFunc("/image.svg", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"><script>alert(2)</script><circle r="4"/></svg>`))
+	})
+	mux.HandleFunc("/revoked", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	mux.HandleFunc("/huge", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte("x"), 2048))
+	})
+
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	conf := newTestConfig()
+	conf.OpenBadge.MaxSize = 1024
+	return srv, NewResolver(conf, srv.Client())
+}
+
+func TestResolveHostedAssertion(t *testing.T) {
+	srv, r := newHostedBadge(t)
+
+	badge, err := r.Resolve(context.Background(), []byte(srv.URL+"/assertion"), "assertion")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if badge.Name != "Go Developer" || badge.Issuer != "Example Academy" {
+		t.Errorf("badge = %q by %q, want Go Developer by Example Academy", badge.Name, badge.Issuer)
+	}
+	if badge.IssuedAt.Format("2006-01-02") != "2024-05-01" || badge.ExpiresAt != nil {
+		t.Errorf("issued %v expires %v, want 2024-05-01 without expiry", badge.IssuedAt, badge.ExpiresAt)
+	}
+	if badge.CredentialID != srv.URL+"/assertion" || badge.VerificationUrl != srv.URL+"/assertion" {
+		t.Errorf("credential %q verification %q", badge.CredentialID, badge.VerificationUrl)
+	}
+	if badge.Image != srv.URL+"/image.svg" {
+		t.Errorf("image = %q", badge.Image)
+	}
+
+	data, ext, err := r.Image(context.Background(), badge.Image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ext != ".svg" || strings.Contains(string(data), "alert") || !strings.Contains(string(data), "<circle") {
+		t.Errorf("image %s = %s, want the svg without script", ext, data)
+	}
+}
+
+func TestResolveFetchErrors(t *testing.T) {
+	srv, r := newHostedBadge(t)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/revoked", http.StatusUnprocessableEntity},
+		{"/missing", http.StatusBadGateway},
+		{"/huge", http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		_, err := r.Resolve(context.Background(), []byte(srv.URL+tt.path), "assertion")
+		if code := testutil.StatusOf(err); code != tt.code {
+			t.Errorf("%s: status = %d (%v), want %d", tt.path, code, err, tt.code)
+		}
+	}
+}
+
+// bakePNG returns a minimal PNG carrying text in an iTXt chunk with the openbadges keyword.
+func bakePNG(text string) []byte {
+	var buf bytes.Buffer
+	buf.Write(pngSignature)
+
+	chunk := func(typ string, data []byte) {
+		binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+		buf.WriteString(typ)
+		buf.Write(data)
+		buf.Write([]byte{0, 0, 0, 0})
+	}
+	chunk("IHDR", make([]byte, 13))
+	chunk("iTXt", append([]byte("openbadges\x00\x00\x00\x00\x00"), text...))
+	chunk("IEND", nil)
+
+	return buf.Bytes()
+}
+
+func TestResolveBakedPNG(t *testing.T) {
+	_, r := newHostedBadge(t)
+
+	assertion := `{"id":"urn:uuid:1","issuedOn":"2024-01-02","expires":"2027-01-02",` +
+		`"badge":{"name":"Embedded","image":"data:image/png;base64,iVBORw0KGgo=","issuer":{"name":"Issuer"},"tags":["a"]}}`
+
+	badge, err := r.Resolve(context.Background(), bakePNG(assertion), "badge.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if badge.Name != "Embedded" || badge.Issuer != "Issuer" || badge.ExpiresAt == nil {
+		t.Errorf("badge = %+v", badge)
+	}
+	if badge.VerificationUrl != "" {
+		t.Errorf("verification url = %q, want none for a urn id", badge.VerificationUrl)
+	}
+	if !slices.Equal(badge.Tags, []string{"a"}) {
+		t.Errorf("tags = %v", badge.Tags)
+	}
+
+	if _, err := r.Resolve(context.Background(), bakePNG(""), "plain.png"); testutil.StatusOf(err) == 0 {
+		t.Errorf("a png without assertion resolved, err = %v", err)
+	}
+}
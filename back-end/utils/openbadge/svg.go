@@ -0,0 +1,186 @@
+package openbadge
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+const (
+	svgNamespace   = "http://www.w3.org/2000/svg"
+	xlinkNamespace = "http://www.w3.org/1999/xlink"
+	xmlNamespace   = "http://www.w3.org/XML/1998/namespace"
+)
+
+// elements that only draw. Everything else is dropped with its content, that covers script,
+// foreignObject, the animation elements that can rewrite attributes, links and anything in
+// another namespace like the baked assertion itself.
+var svgElements = map[string]bool{
+	"svg": true, "g": true, "defs": true, "symbol": true, "use": true, "title": true, "desc": true,
+	"path": true, "rect": true, "circle": true, "ellipse": true, "line": true, "polyline": true, "polygon": true,
+	"text": true, "tspan": true, "textPath": true, "image": true, "style": true,
+	"linearGradient": true, "radialGradient": true, "stop": true, "pattern": true, "clipPath": true, "mask": true,
+	"filter": true, "feGaussianBlur": true, "feOffset": true, "feBlend": true, "feMerge": true, "feMergeNode": true,
+	"feFlood": true, "feComposite": true, "feColorMatrix": true, "feDropShadow": true,
+}
+
+// embedded raster images are the only data urls kept
+var imageDataPrefixes = []string{"data:image/png;base64,", "data:image/jpeg;base64,", "data:image/gif;base64,"}
+
+// SanitizeSVG rebuilds an SVG from the drawing elements and attributes it contains. Event
+// handlers, script urls and references to anything outside the document are removed.
+func SanitizeSVG(data []byte) ([]byte, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = true
+
+	var out, style bytes.Buffer
+	var open []string
+	skip := 0
+	root := true
+
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if skip > 0 {
+				skip++
+				continue
+			}
+			if root && t.Name.Local != "svg" {
+				return nil, errors.New("the root element is not svg")
+			}
+			if !svgElements[t.Name.Local] || (t.Name.Space != svgNamespace && t.Name.Space != "") {
+				skip = 1
+				continue
+			}
+
+			out.WriteString("<" + t.Name.Local)
+			if root {
+				out.WriteString(` xmlns="` + svgNamespace + `" xmlns:xlink="` + xlinkNamespace + `"`)
+				root = false
+			}
+			for _, attr := range t.Attr {
+				name, ok := svgAttrName(attr.Name)
+				if !ok || !safeSVGAttr(t.Name.Local, name, attr.Value) {
+					continue
+				}
+				out.WriteString(" " + name + `="`)
+				xml.EscapeText(&out, []byte(attr.Value))
+				out.WriteString(`"`)
+			}
+			out.WriteString(">")
+			open = append(open, t.Name.Local)
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+			// the text of a style element is checked as a whole, it may come in several parts
+			if open[len(open)-1] == "style" {
+				if safeCSS(style.String()) {
+					xml.EscapeText(&out, style.Bytes())
+				}
+				style.Reset()
+			}
+			out.WriteString("</" + open[len(open)-1] + ">")
+			open = open[:len(open)-1]
+		case xml.CharData:
+			if skip > 0 || len(open) == 0 {
+				continue
+			}
+			if open[len(open)-1] == "style" {
+				style.Write(t)
+				continue
+			}
+			xml.EscapeText(&out, t)
+		}
+		// comments, processing instructions and the doctype are left out
+	}
+
+	if root {
+		return nil, errors.New("no svg element")
+	}
+	return out.Bytes(), nil
+}
+
+// svgAttrName returns the attribute name to write, namespace declarations and attributes of
+// other namespaces are dropped.
+func svgAttrName(name xml.Name) (string, bool) {
+	switch name.Space {
+	case "":
+		return name.Local, name.Local != "xmlns"
+	case xlinkNamespace:
+		return "xlink:" + name.Local, name.Local == "href"
+	case xmlNamespace:
+		return "xml:" + name.Local, true
+	default:
+		return "", false
+	}
+}
+
+func safeSVGAttr(element string, name string, value string) bool {
+	if strings.HasPrefix(strings.ToLower(name), "on") {
+		return false
+	}
+
+	compact := compactLower(value)
+	if name == "href" || name == "xlink:href" {
+		if strings.HasPrefix(compact, "#") {
+			return true
+		}
+		if element != "image" {
+			return false
+		}
+		for _, prefix := range imageDataPrefixes {
+			if strings.HasPrefix(compact, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if strings.Contains(compact, "javascript:") || strings.Contains(compact, "vbscript:") || strings.Contains(compact, "data:") {
+		return false
+	}
+	return safeCSS(value)
+}
+
+// safeCSS allows url() only for references inside the document and no imports. Escapes
+// could spell any of these, so css using them is refused.
+func safeCSS(css string) bool {
+	compact := compactLower(css)
+	if strings.Contains(compact, "\\") || strings.Contains(compact, "@import") || strings.Contains(compact, "expression(") {
+		return false
+	}
+
+	for rest := compact; ; {
+		i := strings.Index(rest, "url(")
+		if i < 0 {
+			return true
+		}
+		rest = strings.TrimLeft(rest[i+len("url("):], `'"`)
+		if !strings.HasPrefix(rest, "#") {
+			return false
+		}
+	}
+}
+
+// compactLower drops whitespace and control characters, which browsers skip when reading
+// a url scheme.
+func compactLower(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(s))
+}
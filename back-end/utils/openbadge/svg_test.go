@@ -0,0 +1,88 @@
+package openbadge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeSVG(t *testing.T) {
+	input := `<?xml version="1.0"?>
+<!-- exported -->
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"
+     xmlns:openbadges="https://openbadges.org" width="10" onload="alert(1)">
+  <openbadges:assertion verify="https://example.com/a/1"><![CDATA[{"id":"x"}]]></openbadges:assertion>
+  <script>alert(2)</script>
+  <foreignObject><div xmlns="http://www.w3.org/1999/xhtml">x</div></foreignObject>
+  <defs><linearGradient id="g"><stop offset="0" stop-color="#fff"/></linearGradient></defs>
+  <a href="javascript:alert(3)"><text>link</text></a>
+  <rect fill="url(#g)" width="10" height="10" onclick="alert(4)"/>
+  <rect style="fill:url(https://evil.example/track)"/>
+  <use xlink:href="#g"/>
+  <use href="https://evil.example/sprite.svg#a"/>
+  <image href="data:image/png;base64,AAAA"/>
+  <image href="data:image/svg+xml;base64,AAAA"/>
+  <animate attributeName="href" to="javascript:alert(5)"/>
+  <set attributeName="onload" to="alert(6)"/>
+  <style>rect { fill: url(#g) }</style>
+  <style>@import url(https://evil.example/a.css);</style>
+  <text x="1">A &amp; B &lt;3</text>
+</svg>`
+
+	out, err := SanitizeSVG([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(out)
+
+	for _, banned := range []string{"script", "alert", "foreignObject", "onload", "onclick", "javascript", "evil.example", "openbadges", "<a", "animate", "<set", "svg+xml", "<!--", "<?xml"} {
+		if strings.Contains(got, banned) {
+			t.Errorf("sanitized svg still contains %q:\n%s", banned, got)
+		}
+	}
+
+	for _, kept := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="10">`,
+		`<rect fill="url(#g)" width="10" height="10">`,
+		`<use xlink:href="#g">`,
+		`<image href="data:image/png;base64,AAAA">`,
+		`<style>rect { fill: url(#g) }</style>`,
+		`<stop offset="0" stop-color="#fff">`,
+		`<text x="1">A &amp; B &lt;3</text>`,
+	} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("sanitized svg lost %q:\n%s", kept, got)
+		}
+	}
+}
+
+func TestSanitizeSVGRejects(t *testing.T) {
+	for name, input := range map[string]string{
+		"not svg":   `<html><body>x</body></html>`,
+		"malformed": `<svg><rect></svg>`,
+		"entity":    `<!DOCTYPE svg [<!ENTITY x "y">]><svg>&x;</svg>`,
+		"empty":     ``,
+	} {
+		if _, err := SanitizeSVG([]byte(input)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSafeCSS(t *testing.T) {
+	tests := map[string]bool{
+		"fill:red":                          true,
+		"fill:url(#a)":                      true,
+		"fill:url('#a')":                    true,
+		"fill:url(https://x)":               false,
+		"fill:URL( //x)":                    false,
+		"fill:url(#a);stroke:url(http://x)": false,
+		`fill:\75 rl(http://x)`:             false,
+		"@import 'x.css'":                   false,
+	}
+
+	for css, want := range tests {
+		if got := safeCSS(css); got != want {
+			t.Errorf("safeCSS(%q) = %v, want %v", css, got, want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/devanadindra/portfolio/back-end/utils/logger"
+)
+
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs jobs in the background of the server process. Each job runs once on start
+// and then every Interval until Stop is called.
+type Scheduler struct {
+	jobs   []Job
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+func New(jobs ...Job) *Scheduler {
+	return &Scheduler{
+		jobs: jobs,
+	}
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, job := range s.jobs {
+		if job.Interval <= 0 {
+			logger.Warn(ctx, "job %s disabled, interval is %s", job.Name, job.Interval)
+			continue
+		}
+
+		s.wg.Add(1)
+		go s.run(ctx, job)
+	}
+}
+
+// Stop cancels the running jobs and waits for them to return.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.execute(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) execute(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error(ctx, "job %s panic : %v", job.Name, r)
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		logger.Error(ctx, "job %s failed : %v", job.Name, err)
+		return
+	}
+
+	logger.Trace(ctx, "job %s done in %s", job.Name, time.Since(start))
+}
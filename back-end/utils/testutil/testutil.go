@@ -0,0 +1,65 @@
+// Package testutil holds the fixtures shared by the tests of the other packages.
+package testutil
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ztrue/tracerr"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	apierror "github.com/devanadindra/portfolio/back-end/utils/api-error"
+)
+
+// NewMockDB returns a postgres gorm connection backed by sqlmock, closed when the test ends.
+func NewMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, mock
+}
+
+// FormFile returns the file header an upload of data named filename produces.
+func FormFile(t *testing.T, filename string, data []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(data)
+	w.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+	return req.MultipartForm.File["file"][0]
+}
+
+// StatusOf returns the http status of an api error, 0 for any other error.
+func StatusOf(err error) int {
+	var apiErr apierror.ApiErrors
+	if errors.As(tracerr.Unwrap(err), &apiErr) {
+		return apiErr.Code
+	}
+	return 0
+}
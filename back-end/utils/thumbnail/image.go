@@ -0,0 +1,98 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+)
+
+var ErrImageTooLarge = errors.New("image dimensions are too large")
+
+// image formats that can be decoded and resized, keyed by their sniffed content type
+var decodableTypes = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpeg",
+	"image/gif":  "gif",
+}
+
+// SniffImage returns the format of an image from its leading bytes ("png", "jpeg" or "gif"),
+// or an empty string when the data is not one of those. The filename is not trusted.
+func SniffImage(data []byte) string {
+	return decodableTypes[http.DetectContentType(data)]
+}
+
+// DecodeImage decodes an image of at most maxPixels pixels. The size is read from the
+// header first so a small file claiming huge dimensions is never decoded.
+func DecodeImage(data []byte, maxPixels int) (image.Image, error) {
+	conf, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Width <= 0 || conf.Height <= 0 || conf.Width > maxPixels/conf.Height {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// Square crops the center square out of src and scales it to size x size. Every target
+// pixel is the average of the source pixels it covers, which keeps downscaled photos
+// smooth; smaller sources are scaled up by repeating pixels.
+func Square(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	origin := image.Pt(
+		bounds.Min.X+(bounds.Dx()-side)/2,
+		bounds.Min.Y+(bounds.Dy()-side)/2,
+	)
+
+	// averaging is done on premultiplied colors so transparent pixels do not bleed
+	crop := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(crop, crop.Bounds(), src, origin, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := span(y, side, size)
+		for x := 0; x < size; x++ {
+			x0, x1 := span(x, side, size)
+
+			var r, g, b, a int
+			for sy := y0; sy < y1; sy++ {
+				row := crop.Pix[sy*crop.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += int(p[0])
+					g += int(p[1])
+					b += int(p[2])
+					a += int(p[3])
+				}
+			}
+
+			n := (y1 - y0) * (x1 - x0)
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
+
+// span returns the source pixels [from, to) covered by target pixel i, at least one.
+func span(i int, side int, size int) (int, int) {
+	from := i * side / size
+	to := (i + 1) * side / size
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
@@ -0,0 +1,129 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSniffImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+
+	var jpg, gf bytes.Buffer
+	if err := jpeg.Encode(&jpg, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := gif.Encode(&gf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "png", data: encodePNG(t, img), want: "png"},
+		{name: "jpeg", data: jpg.Bytes(), want: "jpeg"},
+		{name: "gif", data: gf.Bytes(), want: "gif"},
+		{name: "bmp", data: []byte("BM\x00\x00\x00\x00"), want: ""},
+		{name: "svg", data: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), want: ""},
+		{name: "text", data: []byte("not an image"), want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := SniffImage(tt.data); got != tt.want {
+			t.Errorf("%s: SniffImage() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeImage(t *testing.T) {
+	data := encodePNG(t, image.NewRGBA(image.Rect(0, 0, 40, 30)))
+
+	img, err := DecodeImage(data, 40*30)
+	if err != nil {
+		t.Fatalf("DecodeImage() error = %v", err)
+	}
+	if img.Bounds().Dx() != 40 || img.Bounds().Dy() != 30 {
+		t.Errorf("decoded %v, want 40x30", img.Bounds())
+	}
+
+	if _, err := DecodeImage(data, 40*30-1); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("DecodeImage() error = %v, want ErrImageTooLarge", err)
+	}
+	if _, err := DecodeImage([]byte("not an image"), 1000); err == nil {
+		t.Error("DecodeImage() decoded garbage")
+	}
+}
+
+func TestSquareCropsCenter(t *testing.T) {
+	// a wide image, red on the sides and blue in the center square
+	src := image.NewRGBA(image.Rect(0, 0, 30, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 30; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 10 && x < 20 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			src.SetRGBA(x, y, c)
+		}
+	}
+
+	dst := Square(src, 5)
+	if dst.Bounds() != image.Rect(0, 0, 5, 5) {
+		t.Fatalf("bounds = %v, want 5x5", dst.Bounds())
+	}
+	for _, p := range []image.Point{{0, 0}, {4, 4}, {2, 2}} {
+		if got := dst.RGBAAt(p.X, p.Y); got != (color.RGBA{B: 255, A: 255}) {
+			t.Errorf("pixel %v = %v, want blue", p, got)
+		}
+	}
+}
+
+func TestSquareAverages(t *testing.T) {
+	// black and white columns average to gray when halved
+	src := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			v := uint8(0)
+			if x%2 == 1 {
+				v = 254
+			}
+			src.SetRGBA(x, y, color.RGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+
+	dst := Square(src, 2)
+	if got := dst.RGBAAt(1, 1); got != (color.RGBA{R: 127, G: 127, B: 127, A: 255}) {
+		t.Errorf("pixel = %v, want gray", got)
+	}
+}
+
+func TestSquareUpscales(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	src.SetRGBA(0, 0, color.RGBA{G: 200, A: 255})
+
+	dst := Square(src, 3)
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			if got := dst.RGBAAt(x, y); got != (color.RGBA{G: 200, A: 255}) {
+				t.Errorf("pixel (%d,%d) = %v", x, y, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+)
+
+// ErrRendererUnavailable means the renderer itself is missing, not that the PDF is broken.
+var ErrRendererUnavailable = errors.New("pdf renderer is not available")
+
+// PdfRenderer renders the first page of a PDF into a PNG image.
+type PdfRenderer interface {
+	RenderFirstPage(ctx context.Context, pdfPath string, pngPath string) error
+}
+
+// NewPdfRenderer uses pdftoppm from poppler-utils, which is installed in the docker image so
+// rendering works offline.
+func NewPdfRenderer(conf *config.Config) PdfRenderer {
+	return &pdftoppmRenderer{
+		bin:     conf.Thumbnail.PdftoppmPath,
+		size:    conf.Thumbnail.Size,
+		timeout: conf.Thumbnail.Timeout,
+	}
+}
+
+type pdftoppmRenderer struct {
+	bin     string
+	size    int
+	timeout time.Duration
+}
+
+func (r *pdftoppmRenderer) RenderFirstPage(ctx context.Context, pdfPath string, pngPath string) error {
+	if !strings.HasSuffix(pngPath, ".png") {
+		return fmt.Errorf("thumbnail path %s must end with .png", pngPath)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	// pdftoppm appends the extension itself when -singlefile is used
+	cmd := exec.CommandContext(ctx, r.bin,
+		"-png",
+		"-f", "1",
+		"-l", "1",
+		"-singlefile",
+		"-scale-to", strconv.Itoa(r.size),
+		pdfPath,
+		strings.TrimSuffix(pngPath, ".png"),
+	)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s: %w", ErrRendererUnavailable, r.bin, err)
+		}
+		return fmt.Errorf("failed render pdf thumbnail: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
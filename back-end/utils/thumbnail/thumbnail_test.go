@@ -0,0 +1,47 @@
+package thumbnail
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devanadindra/portfolio/back-end/utils/config"
+)
+
+func newRenderer(bin string) PdfRenderer {
+	conf := &config.Config{}
+	conf.Thumbnail = config.Thumbnail{PdftoppmPath: bin, Size: 100, Timeout: 5 * time.Second}
+	return NewPdfRenderer(conf)
+}
+
+func TestRenderFirstPageMissingBinary(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, bin := range []string{"pdftoppm-does-not-exist", filepath.Join(dir, "pdftoppm")} {
+		err := newRenderer(bin).RenderFirstPage(context.Background(), filepath.Join(dir, "a.pdf"), filepath.Join(dir, "a.png"))
+		if !errors.Is(err, ErrRendererUnavailable) {
+			t.Errorf("%s: err = %v, want ErrRendererUnavailable", bin, err)
+		}
+	}
+}
+
+func TestRenderFirstPageFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	// false exits non zero like pdftoppm does on a broken file
+	err := newRenderer("false").RenderFirstPage(context.Background(), filepath.Join(dir, "a.pdf"), filepath.Join(dir, "a.png"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if errors.Is(err, ErrRendererUnavailable) {
+		t.Errorf("a failed render was reported as a missing renderer: %v", err)
+	}
+}
+
+func TestRenderFirstPageNeedsPng(t *testing.T) {
+	if err := newRenderer("pdftoppm").RenderFirstPage(context.Background(), "a.pdf", "a.jpg"); err == nil {
+		t.Error("expected an error for a non png target")
+	}
+}
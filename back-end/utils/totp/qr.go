@@ -0,0 +1,49 @@
+package totp
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+
+	"github.com/boombuler/barcode/qr"
+)
+
+// quiet zone around the code in modules, scanners need it to find the code
+const quietZone = 4
+
+// QRPNG renders content as a QR code PNG about size pixels wide.
+func QRPNG(content string, size int) ([]byte, error) {
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := code.Bounds().Dx()
+	scale := max(size/(modules+2*quietZone), 1)
+	side := (modules + 2*quietZone) * scale
+
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	for y := 0; y < modules; y++ {
+		for x := 0; x < modules; x++ {
+			if color.GrayModel.Convert(code.At(x, y)).(color.Gray).Y >= 0x80 {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetGray((x+quietZone)*scale+dx, (y+quietZone)*scale+dy, color.Gray{})
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
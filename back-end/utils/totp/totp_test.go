@@ -0,0 +1,124 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// the SHA1 seed of RFC 6238 appendix B, "12345678901234567890" base32 encoded
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// RFC 6238 appendix B, the 8 digit codes cut down to the last 6 digits
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestCode(t *testing.T) {
+	for _, v := range rfcVectors {
+		got, err := Code(rfcSecret, Counter(time.Unix(v.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(%d) error = %v", v.unix, err)
+		}
+		if got != v.code {
+			t.Errorf("Code(%d) = %s, want %s", v.unix, got, v.code)
+		}
+	}
+}
+
+func TestCodeLowercaseSecret(t *testing.T) {
+	got, err := Code(strings.ToLower(rfcSecret), Counter(time.Unix(59, 0)))
+	if err != nil || got != "287082" {
+		t.Errorf("Code() = %s, %v, want 287082", got, err)
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Error("Code() accepted an invalid secret")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	counter := Counter(now)
+
+	tests := []struct {
+		name   string
+		offset int64
+		ok     bool
+	}{
+		{name: "current step", offset: 0, ok: true},
+		{name: "one step behind", offset: -1, ok: true},
+		{name: "one step ahead", offset: 1, ok: true},
+		{name: "two steps behind", offset: -2, ok: false},
+		{name: "two steps ahead", offset: 2, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Code(rfcSecret, counter+tt.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, ok := Validate(rfcSecret, code, now, 1)
+			if ok != tt.ok {
+				t.Fatalf("Validate() ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && got != counter+tt.offset {
+				t.Errorf("Validate() counter = %d, want %d", got, counter+tt.offset)
+			}
+		})
+	}
+}
+
+func TestValidateFormatting(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	if _, ok := Validate(rfcSecret, " 287 082 ", now, 0); !ok {
+		t.Error("Validate() refused a code with spaces")
+	}
+	for _, code := range []string{"", "28708", "2870820", "abcdef"} {
+		if _, ok := Validate(rfcSecret, code, now, 1); ok {
+			t.Errorf("Validate(%q) accepted", code)
+		}
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("secret %q is %d characters, want 32", secret, len(secret))
+	}
+	if _, err := Code(secret, 0); err != nil {
+		t.Errorf("generated secret is not usable: %v", err)
+	}
+}
+
+func TestURI(t *testing.T) {
+	u, err := url.Parse(URI("portfolio", "owner@example.com", rfcSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/portfolio:owner@example.com" {
+		t.Errorf("unexpected uri %s", u)
+	}
+	query := u.Query()
+	if query.Get("secret") != rfcSecret || query.Get("issuer") != "portfolio" || query.Get("digits") != "6" || query.Get("period") != "30" {
+		t.Errorf("unexpected query %v", query)
+	}
+}
@@ -10,13 +10,22 @@ import (
 
 	"github.com/devanadindra/portfolio/back-end/database"
 	"github.com/devanadindra/portfolio/back-end/domains/certif"
+	"github.com/devanadindra/portfolio/back-end/domains/linkcheck"
+	"github.com/devanadindra/portfolio/back-end/domains/notification"
 	"github.com/devanadindra/portfolio/back-end/domains/project"
+	"github.com/devanadindra/portfolio/back-end/domains/search"
 	"github.com/devanadindra/portfolio/back-end/domains/skill"
+	"github.com/devanadindra/portfolio/back-end/domains/tag"
+	"github.com/devanadindra/portfolio/back-end/domains/trash"
 	"github.com/devanadindra/portfolio/back-end/domains/user"
+	"github.com/devanadindra/portfolio/back-end/jobs"
 	"github.com/devanadindra/portfolio/back-end/middlewares"
 	"github.com/devanadindra/portfolio/back-end/routes"
 	"github.com/devanadindra/portfolio/back-end/utils/config"
 	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+	"github.com/devanadindra/portfolio/back-end/utils/mailer"
+	"github.com/devanadindra/portfolio/back-end/utils/openbadge"
+	"github.com/devanadindra/portfolio/back-end/utils/thumbnail"
 )
 
 var dbSet = wire.NewSet(
@@ -28,6 +37,19 @@ var dbSelectorSet = wire.NewSet(
 	dbselector.NewDBService,
 )
 
+var mailerSet = wire.NewSet(
+	mailer.New,
+)
+
+var thumbnailSet = wire.NewSet(
+	thumbnail.NewPdfRenderer,
+)
+
+var openBadgeSet = wire.NewSet(
+	openbadge.NewHTTPClient,
+	openbadge.NewResolver,
+)
+
 var userSet = wire.NewSet(
 	user.NewService,
 	user.NewHandler,
@@ -48,11 +70,44 @@ var certifSet = wire.NewSet(
 	certif.NewHandler,
 )
 
+var searchSet = wire.NewSet(
+	search.NewService,
+	search.NewHandler,
+)
+
+var tagSet = wire.NewSet(
+	tag.NewService,
+	tag.NewHandler,
+)
+
+var trashSet = wire.NewSet(
+	trash.NewService,
+	trash.NewHandler,
+)
+
+var notificationSet = wire.NewSet(
+	notification.NewService,
+	notification.NewHandler,
+)
+
+var linkCheckSet = wire.NewSet(
+	linkcheck.NewHTTPClient,
+	linkcheck.NewService,
+	linkcheck.NewHandler,
+)
+
+var jobSet = wire.NewSet(
+	jobs.NewScheduler,
+)
+
 func initializeDependency(config *config.Config) (*routes.Dependency, error) {
 
 	wire.Build(
 		dbSet,
 		dbSelectorSet,
+		mailerSet,
+		thumbnailSet,
+		openBadgeSet,
 		validator.New,
 		middlewares.NewMiddlewares,
 		routes.NewDependency,
@@ -60,6 +115,12 @@ func initializeDependency(config *config.Config) (*routes.Dependency, error) {
 		projectSet,
 		skillSet,
 		certifSet,
+		searchSet,
+		tagSet,
+		trashSet,
+		notificationSet,
+		linkCheckSet,
+		jobSet,
 	)
 
 	return nil, nil
@@ -9,13 +9,22 @@ package wireinject
 import (
 	"github.com/devanadindra/portfolio/back-end/database"
 	"github.com/devanadindra/portfolio/back-end/domains/certif"
+	"github.com/devanadindra/portfolio/back-end/domains/linkcheck"
+	"github.com/devanadindra/portfolio/back-end/domains/notification"
 	"github.com/devanadindra/portfolio/back-end/domains/project"
+	"github.com/devanadindra/portfolio/back-end/domains/search"
 	"github.com/devanadindra/portfolio/back-end/domains/skill"
+	"github.com/devanadindra/portfolio/back-end/domains/tag"
+	"github.com/devanadindra/portfolio/back-end/domains/trash"
 	"github.com/devanadindra/portfolio/back-end/domains/user"
+	"github.com/devanadindra/portfolio/back-end/jobs"
 	"github.com/devanadindra/portfolio/back-end/middlewares"
 	"github.com/devanadindra/portfolio/back-end/routes"
 	"github.com/devanadindra/portfolio/back-end/utils/config"
 	"github.com/devanadindra/portfolio/back-end/utils/dbselector"
+	"github.com/devanadindra/portfolio/back-end/utils/mailer"
+	"github.com/devanadindra/portfolio/back-end/utils/openbadge"
+	"github.com/devanadindra/portfolio/back-end/utils/thumbnail"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/wire"
 )
@@ -36,17 +45,36 @@ func initializeDependency(config2 *config.Config) (*routes.Dependency, error) {
 		return nil, err
 	}
 	dbService := dbselector.NewDBService(ownerDB, visitorsDB)
-	service := user.NewService(config2, dbService, visitorsDB, ownerDB)
+	sender, err := mailer.New(config2)
+	if err != nil {
+		return nil, err
+	}
+	service := user.NewService(config2, dbService, visitorsDB, ownerDB, sender)
 	middlewaresMiddlewares := middlewares.NewMiddlewares(config2, service)
 	validate := validator.New()
-	handler := user.NewHandler(service, validate)
+	handler := user.NewHandler(config2, service, validate)
 	projectService := project.NewService(config2, dbService, visitorsDB, ownerDB)
 	projectHandler := project.NewHandler(projectService, validate)
 	skillService := skill.NewService(config2, dbService, visitorsDB, ownerDB)
 	skillHandler := skill.NewHandler(skillService, validate)
-	certifService := certif.NewService(config2, dbService, visitorsDB, ownerDB)
+	pdfRenderer := thumbnail.NewPdfRenderer(config2)
+	httpClient := openbadge.NewHTTPClient(config2)
+	resolver := openbadge.NewResolver(config2, httpClient)
+	certifService := certif.NewService(config2, dbService, visitorsDB, ownerDB, sender, pdfRenderer, resolver)
 	certifHandler := certif.NewHandler(certifService, validate)
-	dependency := routes.NewDependency(config2, middlewaresMiddlewares, ownerDB, visitorsDB, handler, projectHandler, skillHandler, certifHandler)
+	searchService := search.NewService(config2, dbService, visitorsDB, ownerDB)
+	searchHandler := search.NewHandler(searchService, validate)
+	tagService := tag.NewService(config2, dbService, visitorsDB, ownerDB)
+	tagHandler := tag.NewHandler(tagService, validate)
+	trashService := trash.NewService(config2, dbService, visitorsDB, ownerDB)
+	trashHandler := trash.NewHandler(trashService, validate)
+	notificationService := notification.NewService(config2, dbService, visitorsDB, ownerDB)
+	notificationHandler := notification.NewHandler(notificationService, validate)
+	linkcheckHTTPClient := linkcheck.NewHTTPClient(config2)
+	linkcheckService := linkcheck.NewService(config2, dbService, visitorsDB, ownerDB, linkcheckHTTPClient)
+	linkcheckHandler := linkcheck.NewHandler(linkcheckService, validate)
+	scheduler := jobs.NewScheduler(trashService, certifService, linkcheckService)
+	dependency := routes.NewDependency(config2, middlewaresMiddlewares, ownerDB, visitorsDB, handler, projectHandler, skillHandler, certifHandler, searchHandler, tagHandler, trashHandler, notificationHandler, linkcheckHandler, scheduler)
 	return dependency, nil
 }
 
@@ -56,6 +84,12 @@ var dbSet = wire.NewSet(database.NewDBCustomer, database.NewDBAdmin)
 
 var dbSelectorSet = wire.NewSet(dbselector.NewDBService)
 
+var mailerSet = wire.NewSet(mailer.New)
+
+var thumbnailSet = wire.NewSet(thumbnail.NewPdfRenderer)
+
+var openBadgeSet = wire.NewSet(openbadge.NewHTTPClient, openbadge.NewResolver)
+
 var userSet = wire.NewSet(user.NewService, user.NewHandler)
 
 var projectSet = wire.NewSet(project.NewService, project.NewHandler)
@@ -63,3 +97,15 @@ var projectSet = wire.NewSet(project.NewService, project.NewHandler)
 var skillSet = wire.NewSet(skill.NewService, skill.NewHandler)
 
 var certifSet = wire.NewSet(certif.NewService, certif.NewHandler)
+
+var searchSet = wire.NewSet(search.NewService, search.NewHandler)
+
+var tagSet = wire.NewSet(tag.NewService, tag.NewHandler)
+
+var trashSet = wire.NewSet(trash.NewService, trash.NewHandler)
+
+var notificationSet = wire.NewSet(notification.NewService, notification.NewHandler)
+
+var linkCheckSet = wire.NewSet(linkcheck.NewHTTPClient, linkcheck.NewService, linkcheck.NewHandler)
+
+var jobSet = wire.NewSet(jobs.NewScheduler)